@@ -4,8 +4,10 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 )
 
 type BindTestQuery struct {
@@ -97,3 +99,42 @@ func BenchmarkBind(b *testing.B) {
 		})
 	}
 }
+
+type BindTestScalar struct {
+	Ak   string        `gbind:"http.query.ak,default=ak-default"`
+	Ts   int64         `gbind:"http.query.ts"`
+	Page int           `gbind:"http.query.page,default=1"`
+	Host string        `gbind:"http.header.x-host"`
+	TTL  time.Duration `gbind:"http.header.X-TTL"`
+}
+
+func newScalarReq() *http.Request {
+	return newReq().
+		addQueryParam("ak", "ak1").
+		addQueryParam("ts", "123456789").
+		addHeader("x-host", "www.baidu.com").
+		addHeader("X-TTL", "1s").r()
+}
+
+func TestBindScalarAllocs(t *testing.T) {
+	req := newScalarReq()
+	value := &BindTestScalar{}
+	allocs := testing.AllocsPerRun(100, func() {
+		*value = BindTestScalar{}
+		if _, err := Bind(context.Background(), value, req); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, BindTestScalar{Ak: "ak1", Ts: 123456789, Page: 1, Host: "www.baidu.com", TTL: time.Second}, *value)
+	// only the context passed to the execers and the validator, which may be kept by them
+	assert.Equal(t, float64(1), allocs)
+}
+
+func BenchmarkBindScalar(b *testing.B) {
+	req := newScalarReq()
+	value := &BindTestScalar{}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		Bind(context.Background(), value, req)
+	}
+}
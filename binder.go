@@ -0,0 +1,93 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// HTTPBinder is implemented by the structs generated by gbindgen,
+// Gbind.Bind calls BindHTTP instead of setting the fields bound by the gbind tag with the reflection,
+// the json body, the sanitizers and the validation are still handled by Gbind.Bind.
+// The generated code applies the gbind tag with the default options, so that BindHTTP is only called by
+// the Gbind with the default bind tag, split flag and collection format and without WithMaxSliceLen
+// and WithMaxStringLen, for the structs bound by the http sources and the built-in transforms
+type HTTPBinder interface {
+	BindHTTP(ctx context.Context, r *http.Request) error
+}
+
+// ErrNilRequest is returned by the generated BindHTTP if the http.Request is nil
+var ErrNilRequest = e("cannot bind from a nil http.Request")
+
+// PostForm returns the form values of the http.form source, it is used by the code generated by gbindgen,
+// the form is parsed with the limits of the Gbind if BindHTTP is called by Gbind.Bind
+func PostForm(ctx context.Context, r *http.Request) (url.Values, error) {
+	md, ok := ctx.Value(metaKey{}).(*httpMetaData)
+	if !ok || md.request != r {
+		md = &httpMetaData{request: r}
+	}
+	md.initFormCache()
+	return md.formCache, md.formErr
+}
+
+// IndexedValues returns the values of uids[0]=1&uids[1]=2 in the order of the index like CollectionIndexed,
+// it is used by the code generated by gbindgen
+func IndexedValues(values url.Values, key string) []string {
+	return indexedValues(values, key)
+}
+
+// httpBinder returns the HTTPBinder generated by gbindgen if data is a http request
+func httpBinder(v interface{}, data interface{}) (HTTPBinder, *http.Request, bool) {
+	b, ok := v.(HTTPBinder)
+	if !ok {
+		return nil, nil, false
+	}
+	req, ok := data.(*http.Request)
+	return b, req, ok && req != nil
+}
+
+// bindHTTP calls BindHTTP of the generated code, the context carries the httpMetaData with the limits of the Gbind
+func bindHTTP(ctx context.Context, b HTTPBinder, req *http.Request) (context.Context, error) {
+	ctx = newHTTPContext(ctx, req)
+	return ctx, b.BindHTTP(ctx, req)
+}
+
+// generatedOptions reports whether the options are the same as the ones applied by the generated code
+func (opt *options) generatedOptions() bool {
+	return opt.bindTagName == defaultBindTag && opt.defaultSplitFlag == defaultSplitFlag &&
+		opt.collectionFormat == CollectionMulti && opt.limits.maxSliceLen == 0 && opt.limits.maxStringLen == 0
+}
+
+// ParseInt parses s as an integer, an empty string is zero like the reflection binding,
+// ParseInt ParseUint ParseFloat and ParseBool are used by the code generated by gbindgen
+func ParseInt(s string, bitSize int) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, bitSize)
+}
+
+// ParseUint parses s as an unsigned integer, an empty string is zero
+func ParseUint(s string, bitSize int) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, bitSize)
+}
+
+// ParseFloat parses s as a float, an empty string is zero
+func ParseFloat(s string, bitSize int) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, bitSize)
+}
+
+// ParseBool parses s as a bool, an empty string is false
+func ParseBool(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
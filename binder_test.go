@@ -0,0 +1,129 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generatedParams implements HTTPBinder like the code generated by gbindgen
+type generatedParams struct {
+	Appkey string   `gbind:"http.query.appkey,trim"`
+	Uids   []string `gbind:"http.form.uids,default=1|2"`
+	calls  int
+}
+
+func (v *generatedParams) BindHTTP(ctx context.Context, r *http.Request) error {
+	v.calls++
+	if r == nil {
+		return ErrNilRequest
+	}
+	query := r.URL.Query()
+	form, err := PostForm(ctx, r)
+	if err != nil {
+		return err
+	}
+	if vs := query["appkey"]; len(vs) > 0 {
+		v.Appkey = strings.TrimSpace(vs[0])
+	}
+	if vs := form["uids"]; len(vs) > 0 {
+		v.Uids = append([]string(nil), vs...)
+	} else {
+		v.Uids = []string{"1", "2"}
+	}
+	return nil
+}
+
+func TestHTTPBinder(t *testing.T) {
+	req := func() *http.Request {
+		return newReq().addQueryParam("appkey", " abc ").r()
+	}
+
+	// the default options are applied by BindHTTP
+	p := &generatedParams{}
+	_, err := NewGbind().Bind(context.Background(), p, req())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, p.calls)
+	assert.Equal(t, "abc", p.Appkey)
+	assert.Equal(t, []string{"1", "2"}, p.Uids)
+
+	// not a http request, the fields are bound with the reflection
+	p = &generatedParams{}
+	_, err = NewGbind().Bind(context.Background(), p, nil)
+	assert.Equal(t, "data is not a pointer of http.Request", err.Error())
+	assert.Equal(t, 0, p.calls)
+
+	// the options different from the generated code are applied with the reflection
+	p = &generatedParams{}
+	_, err = NewGbind(WithDefaultSplitFlag("-")).Bind(context.Background(), p, req())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, p.calls)
+	assert.Equal(t, []string{"1|2"}, p.Uids)
+
+	// a replaced built-in transform
+	g := NewGbind()
+	p = &generatedParams{}
+	_, err = g.Bind(context.Background(), p, req())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, p.calls)
+	g.RegisterTransform("trim", func(s string) (string, error) {
+		return strings.Trim(s, " a"), nil
+	})
+	p = &generatedParams{}
+	_, err = g.Bind(context.Background(), p, req())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, p.calls)
+	assert.Equal(t, "bc", p.Appkey)
+
+	// the limits of the Gbind are applied to the form parsed by PostForm
+	p = &generatedParams{}
+	r, _ := http.NewRequest("POST", "http://localhost:8080/", strings.NewReader("uids=12345"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = NewGbind(WithMaxBodyBytes(4)).Bind(context.Background(), p, r)
+	assert.Equal(t, 1, p.calls)
+	assert.Equal(t, &LimitError{Limit: LimitBodyBytes, Max: 4}, err)
+
+	err = p.BindHTTP(context.Background(), nil)
+	assert.Equal(t, ErrNilRequest, err)
+}
+
+func TestPostForm(t *testing.T) {
+	form, err := PostForm(context.Background(), newReq().addFormParam("uids", "1").r())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, form["uids"])
+
+	// the form parsed by the binding is reused
+	req := newReq().addFormParam("uids", "2").r()
+	ctx := newHTTPContext(context.Background(), req)
+	form, err = PostForm(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2"}, form["uids"])
+	assert.Equal(t, mustContextHTTPMeta(ctx).formCache, form)
+}
+
+func TestIndexedValues(t *testing.T) {
+	values := url.Values{"uids[1]": {"2"}, "uids[0]": {"1"}, "uids[x]": {"3"}}
+	assert.Equal(t, []string{"1", "2"}, IndexedValues(values, "uids"))
+	assert.Nil(t, IndexedValues(values, "ids"))
+}
+
+func TestParse(t *testing.T) {
+	i, err := ParseInt("", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), i)
+	_, err = ParseInt("300", 8)
+	assert.NotNil(t, err)
+	u, err := ParseUint("7", 8)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), u)
+	f, err := ParseFloat("", 64)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), f)
+	b, err := ParseBool("")
+	assert.Nil(t, err)
+	assert.False(t, b)
+}
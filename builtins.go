@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/bdjimmy/gbind/internal/tags"
 )
 
 // ---------- http execer start ----------
@@ -35,45 +38,50 @@ var (
 	errHTTPHead   = errors.New("syntax error: http head error")
 	errHTTPCookie = errors.New("syntax error: http cookie error")
 	errHTTPPost   = errors.New("syntax error: http post error")
+
+	durationType = reflect.TypeOf(time.Duration(0))
 )
 
+// httpArityErrs the errors of the http sources with the wrong number of the parts
+var httpArityErrs = map[string]error{
+	"query":  errHTTPQuery,
+	"path":   errHTTPPath,
+	"header": errHTTPHead,
+	"cookie": errHTTPCookie,
+	"form":   errHTTPPost,
+}
+
 // newHTTPExecer Execer are generated based on the values
 func newHTTPExecer(values [][]byte) (Execer, error) {
 	n := len(values)
 	if n < 2 {
 		return nil, errHTTP
 	}
+	arity, ok := tags.HTTPArity[string(values[1])]
+	if !ok {
+		return nil, fmt.Errorf("syntax error: not support http %s", values[1])
+	}
+	if n != arity {
+		return nil, httpArityErrs[string(values[1])]
+	}
 	switch {
 	case bytes.Equal(values[1], httpQueryID):
-		if n != 3 {
-			return nil, errHTTPQuery
-		}
 		return &httpQueryExcer{
 			param: SliceToString(values[2]),
 		}, nil
 	case bytes.Equal(values[1], httpPathID):
-		if n != 2 {
-			return nil, errHTTPPath
-		}
 		return &httpPathExcer{}, nil
 	case bytes.Equal(values[1], httpHeadID):
-		if n != 3 {
-			return nil, errHTTPHead
-		}
+		param := SliceToString(values[2])
 		return &httpHeadExcer{
-			param: SliceToString(values[2]),
+			param: param,
+			key:   textproto.CanonicalMIMEHeaderKey(param),
 		}, nil
 	case bytes.Equal(values[1], httpCookieID):
-		if n != 3 {
-			return nil, errHTTPCookie
-		}
 		return &httpCookieExcer{
 			param: SliceToString(values[2]),
 		}, nil
 	case bytes.Equal(values[1], httpPostID):
-		if n != 3 {
-			return nil, errHTTPPost
-		}
 		return &httpFormExcer{
 			param: SliceToString(values[2]),
 		}, nil
@@ -90,10 +98,15 @@ func (h *httpPathExcer) Exec(ctx context.Context, value reflect.Value, data inte
 	if !ok {
 		return ctx, errHTTPPath
 	}
-	err := TrySet(value, []string{req.URL.Path}, opt)
+	ctx, vs, _ := h.values(ctx, req, opt)
+	err := TrySetWithContext(ctx, value, vs, opt)
 	return ctx, err
 }
 
+func (h *httpPathExcer) values(ctx context.Context, req *http.Request, opt *DefaultOption) (context.Context, []string, error) {
+	return ctx, []string{req.URL.Path}, nil
+}
+
 // Name
 func (h *httpPathExcer) Name() string {
 	return "http.path"
@@ -109,12 +122,16 @@ func (h *httpQueryExcer) Exec(ctx context.Context, value reflect.Value, data int
 	if !ok {
 		return ctx, errors.New("data is not a pointer of http.Request")
 	}
-	ctx = newHTTPContext(ctx, req)
-	vs := mustContextHTTPMeta(ctx).getQueryArray(h.param)
-	err := TrySet(value, vs, opt)
+	ctx, vs, _ := h.values(ctx, req, opt)
+	err := TrySetWithContext(ctx, value, vs, opt)
 	return ctx, err
 }
 
+func (h *httpQueryExcer) values(ctx context.Context, req *http.Request, opt *DefaultOption) (context.Context, []string, error) {
+	ctx = newHTTPContext(ctx, req)
+	return ctx, mustContextHTTPMeta(ctx).queryArray(h.param, opt.collectionFormat()), nil
+}
+
 func (h *httpQueryExcer) Name() string {
 	return "http.query"
 }
@@ -122,6 +139,8 @@ func (h *httpQueryExcer) Name() string {
 // ----------------- http.head -----------------
 type httpHeadExcer struct {
 	param string
+	// key the canonical key of param
+	key string
 }
 
 func (h *httpHeadExcer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
@@ -129,7 +148,12 @@ func (h *httpHeadExcer) Exec(ctx context.Context, value reflect.Value, data inte
 	if !ok {
 		return ctx, errors.New("data is not a pointer of http.Request")
 	}
-	return ctx, TrySet(value, req.Header.Values(h.param), opt)
+	ctx, vs, _ := h.values(ctx, req, opt)
+	return ctx, TrySetWithContext(ctx, value, vs, opt)
+}
+
+func (h *httpHeadExcer) values(ctx context.Context, req *http.Request, opt *DefaultOption) (context.Context, []string, error) {
+	return ctx, req.Header[h.key], nil
 }
 
 func (h *httpHeadExcer) Name() string {
@@ -146,12 +170,20 @@ func (h *httpFormExcer) Exec(ctx context.Context, value reflect.Value, data inte
 	if !ok {
 		return ctx, errors.New("data is not a pointer of http.Request")
 	}
-	ctx = newHTTPContext(ctx, req)
-	vs := mustContextHTTPMeta(ctx).getFormArray(h.param)
-	err := TrySet(value, vs, opt)
+	ctx, vs, err := h.values(ctx, req, opt)
+	if err != nil {
+		return ctx, err
+	}
+	err = TrySetWithContext(ctx, value, vs, opt)
 	return ctx, err
 }
 
+func (h *httpFormExcer) values(ctx context.Context, req *http.Request, opt *DefaultOption) (context.Context, []string, error) {
+	ctx = newHTTPContext(ctx, req)
+	vs, err := mustContextHTTPMeta(ctx).getFormArray(h.param, opt.collectionFormat())
+	return ctx, vs, err
+}
+
 func (h *httpFormExcer) Name() string {
 	return "http.form"
 }
@@ -166,14 +198,17 @@ func (h *httpCookieExcer) Exec(ctx context.Context, value reflect.Value, data in
 	if !ok {
 		return ctx, errors.New("data is not a pointer of http.Request")
 	}
+	ctx, vs, _ := h.values(ctx, req, opt)
+	err := TrySetWithContext(ctx, value, vs, opt)
+	return ctx, err
+}
+
+func (h *httpCookieExcer) values(ctx context.Context, req *http.Request, opt *DefaultOption) (context.Context, []string, error) {
 	if c, err := req.Cookie(h.param); err == nil {
 		v, _ := url.QueryUnescape(c.Value)
-		err := TrySet(value, []string{v}, opt)
-		return ctx, err
+		return ctx, []string{v}, nil
 	}
-	err := TrySet(value, []string{}, opt)
-	return ctx, err
-
+	return ctx, []string{}, nil
 }
 
 func (h *httpCookieExcer) Name() string {
@@ -182,85 +217,96 @@ func (h *httpCookieExcer) Name() string {
 
 // --------- http execer end ---------
 
+// DefaultFunc generates the default value of the field when the source has no value
+type DefaultFunc func(ctx context.Context, field reflect.StructField) (string, error)
+
 // DefaultOption options for the default values
 type DefaultOption struct {
 	IsDefaultExists  bool
 	DefaultValue     string
 	DefaultSplitFlag string
+	// CollectionFormat the format of the slice values, only set for slice and array fields
+	CollectionFormat CollectionFormat
+
+	// transform pipeline of the bind tag
+	transforms []transformer
+	// setter sets the values to the field of setterType, selected when compiled
+	setter     setter
+	setterType reflect.Type
+	// defaults the DefaultValue split by DefaultSplitFlag when compiled, it is read-only
+	defaults []string
+	// default_func of the bind tag and the field it belongs to
+	defaultFunc DefaultFunc
+	field       reflect.StructField
+	// limits of the field, max_items= and max_len= of the bind tag
+	namespace string
+	maxItems  int
+	maxLen    int
+}
+
+func (opt *DefaultOption) collectionFormat() CollectionFormat {
+	if opt == nil {
+		return CollectionMulti
+	}
+	return opt.CollectionFormat
 }
 
 // TrySet try to set up the value
-// A custom callback function can invoke this function
+// A custom callback function can invoke this function,
+// the default_func of the field is called with context.Background()
 func TrySet(value reflect.Value, vs []string, opt *DefaultOption) error {
-	var def = false
-	if opt != nil && opt.IsDefaultExists {
-		def = true
-	}
-	if len(vs) == 0 && !def {
-		return nil
-	}
+	return TrySetWithContext(context.Background(), value, vs, opt)
+}
 
-	if len(vs) == 0 && def {
-		vs = strings.Split(opt.DefaultValue, opt.DefaultSplitFlag)
+// TrySetWithContext try to set up the value, ctx is passed to the default_func of the field
+// A custom callback function can invoke this function
+func TrySetWithContext(ctx context.Context, value reflect.Value, vs []string, opt *DefaultOption) error {
+	kind := value.Kind()
+	vs, err := opt.values(ctx, vs, kind == reflect.Slice || kind == reflect.Array)
+	if err != nil || len(vs) == 0 {
+		return err
 	}
-	switch value.Interface().(type) {
-	case time.Duration:
-		return setTimeDuration(vs, value)
+	if opt != nil && opt.setter != nil && opt.setterType == value.Type() {
+		return opt.setter(value, vs)
 	}
+	return newSetter(value.Type())(value, vs)
+}
 
-	switch value.Kind() {
-	case reflect.Slice:
-		return setSlice(vs, value)
-	case reflect.Array:
-		if len(vs) != value.Len() {
-			return fmt.Errorf("%q is not valid value for %s", vs, value.Type().String())
-		}
-		return setArray(vs, value)
-	default:
-		var val string
-		if len(vs) > 0 {
-			val = vs[0]
-		}
-		return setWithProperType(val, value)
-	}
+// Set sets vs to the value of the field like TrySetWithContext, by the setter selected when the field
+// is compiled, the custom execers call it with the value and the opt passed to Exec
+func (opt *DefaultOption) Set(ctx context.Context, value reflect.Value, vs []string) error {
+	return TrySetWithContext(ctx, value, vs, opt)
 }
 
-func setWithProperType(val string, value reflect.Value) error {
-	switch value.Kind() {
-	case reflect.Bool:
-		return setBoolField(val, value)
-	// float
-	case reflect.Float32:
-		return setFloatField(val, 32, value)
-	case reflect.Float64:
-		return setFloatField(val, 64, value)
-	// int
-	case reflect.Int:
-		return setIntField(val, 0, value)
-	case reflect.Int8:
-		return setIntField(val, 8, value)
-	case reflect.Int16:
-		return setIntField(val, 16, value)
-	case reflect.Int32:
-		return setIntField(val, 32, value)
-	case reflect.Int64:
-		return setIntField(val, 64, value)
-	// uint
-	case reflect.Uint:
-		return setUintField(val, 0, value)
-	case reflect.Uint8:
-		return setUintField(val, 8, value)
-	case reflect.Uint16:
-		return setUintField(val, 16, value)
-	case reflect.Uint32:
-		return setUintField(val, 32, value)
-	case reflect.Uint64:
-		return setUintField(val, 64, value)
-	// string
-	case reflect.String:
-		value.SetString(val)
+// values applies the transforms and the limits to vs, or returns the default values if vs is empty,
+// nothing should be set if no values are returned
+func (opt *DefaultOption) values(ctx context.Context, vs []string, isSlice bool) ([]string, error) {
+	if opt == nil {
+		return vs, nil
+	}
+	if len(vs) > 0 {
+		var err error
+		if len(opt.transforms) > 0 {
+			if vs, err = transform(vs, opt.transforms); err != nil {
+				return nil, err
+			}
+		}
+		return vs, opt.checkValues(vs, isSlice)
+	}
+	if !opt.IsDefaultExists && opt.defaultFunc == nil {
+		return nil, nil
 	}
-	return nil
+	if opt.defaultFunc == nil && opt.defaults != nil {
+		return opt.defaults, nil
+	}
+	defaultValue := opt.DefaultValue
+	if opt.defaultFunc != nil {
+		var err error
+		if defaultValue, err = opt.defaultFunc(ctx, opt.field); err != nil {
+			return nil, err
+		}
+	}
+	return strings.Split(defaultValue, opt.DefaultSplitFlag), nil
 }
 
 func setUintField(val string, bitSize int, field reflect.Value) error {
@@ -313,27 +359,7 @@ func setTimeDuration(vals []string, field reflect.Value) error {
 	}
 	d, err := time.ParseDuration(vals[0])
 	if err == nil {
-		field.Set(reflect.ValueOf(d))
+		field.SetInt(int64(d))
 	}
 	return err
 }
-
-func setArray(vals []string, value reflect.Value) error {
-	for i, s := range vals {
-		err := setWithProperType(s, value.Index(i))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func setSlice(vals []string, value reflect.Value) error {
-	slice := reflect.MakeSlice(value.Type(), len(vals), len(vals))
-	err := setArray(vals, slice)
-	if err != nil {
-		return err
-	}
-	value.Set(slice)
-	return nil
-}
@@ -1,35 +1,254 @@
 package gbind
 
 import (
+	"container/list"
 	"reflect"
 	"sync"
 	"sync/atomic"
 )
 
+// CacheStats the counters of the cache of the compiled structs
+type CacheStats struct {
+	// Hits and Misses the lookups of the cache
+	Hits   uint64
+	Misses uint64
+	// Compiles the structs compiled after the misses
+	Compiles uint64
+	// Evictions the least recently used structs removed by the bound of WithCacheSize
+	Evictions uint64
+	// Size the number of the cached structs
+	Size int
+}
+
+// WithCacheSize bounds the cache of the compiled structs to n types, the least recently used
+// type is evicted when it is full, the hits do not lock the cache, the cache is unbounded if n is not positive, which is the default.
+// Bound the cache when binding to the types created dynamically, e.g. by reflect.StructOf
+func WithCacheSize(n int) OptApply {
+	return func(opt *options) {
+		opt.cacheSize = n
+	}
+}
+
+// cache the compiled structs by reflect.Type in a sync.Map which is read without locking,
+// the bounded cache keeps the entries in a list ordered by the stamps of their last use, the hits only
+// stamp the entries by an atomic clock, the list is reordered by the stamps when evicting on the insertions
 type cache struct {
-	lock sync.Mutex
-	m    atomic.Value
+	// maxSize the maximum number of the types, unbounded if it is not positive
+	maxSize int
+	// m reflect.Type => *cacheEntry
+	m sync.Map
+	// clock the last stamp of the bounded cache, it is advanced by every hit and insertion
+	clock atomic.Uint64
+	// flight guards calls and lru, the writes of m are done with it held
+	flight sync.Mutex
+	// calls the compilations in flight
+	calls map[reflect.Type]*compileCall
+	// lru the *cacheEntry of the bounded cache ordered by listed, the front is the least recently used
+	lru *list.List
+
+	size      atomic.Int64
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	compiles  atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// cacheEntry the cached struct and the clock of its last use
+type cacheEntry struct {
+	key   reflect.Type
+	value *structType
+	stamp atomic.Uint64
+	// elem and listed the element in the lru and the stamp it is ordered by, the entry is used
+	// after it was listed if stamp differs from listed
+	elem   *list.Element
+	listed uint64
+}
+
+// compileCall a compilation in flight, the other callers wait for its result
+type compileCall struct {
+	wg  sync.WaitGroup
+	st  *structType
+	err error
+}
+
+func newCache(maxSize int) *cache {
+	return &cache{maxSize: maxSize, calls: map[reflect.Type]*compileCall{}, lru: list.New()}
 }
 
-func newCache() *cache {
-	c := &cache{}
-	c.m.Store(make(map[reflect.Type]*structType))
-	return c
+func (c *cache) get(key reflect.Type) (st *structType, found bool) {
+	st, found = c.load(key)
+	if found {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return st, found
+}
+
+// load looks up the cache without counting, the hit of the bounded cache only stamps the entry
+// by the next clock, the lru is not touched
+func (c *cache) load(key reflect.Type) (st *structType, found bool) {
+	v, found := c.m.Load(key)
+	if !found {
+		return nil, false
+	}
+	entry := v.(*cacheEntry)
+	if c.maxSize > 0 {
+		entry.stamp.Store(c.clock.Add(1))
+	}
+	return entry.value, true
+}
+
+// do compiles the key by fn and caches the result, the concurrent calls of the same key wait for
+// the first one and share its result instead of compiling again
+func (c *cache) do(key reflect.Type, fn func(reflect.Type) (*structType, error)) (*structType, error) {
+	c.flight.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.flight.Unlock()
+		call.wg.Wait()
+		return call.st, call.err
+	}
+	// compiled by a call which has finished after the miss of the caller
+	if st, ok := c.load(key); ok {
+		c.flight.Unlock()
+		return st, nil
+	}
+	// the waiters get the error if fn panics
+	call := &compileCall{err: e("compiling %v panicked", key)}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.flight.Unlock()
+	defer func() {
+		c.flight.Lock()
+		delete(c.calls, key)
+		c.flight.Unlock()
+		call.wg.Done()
+	}()
+
+	call.st, call.err = fn(key)
+	if call.err == nil {
+		c.compiles.Add(1)
+		// the struct compiled before a registration is used by the callers but not cached,
+		// the flight lock orders it with the invalidation of the registration
+		c.flight.Lock()
+		if call.st.current() {
+			c.set(key, call.st)
+		}
+		c.flight.Unlock()
+	}
+	return call.st, call.err
 }
 
-func (c *cache) get(key reflect.Type) (g *structType, found bool) {
-	g, found = c.m.Load().(map[reflect.Type]*structType)[key]
-	return
+// invalidate removes the cached structs which are stale
+func (c *cache) invalidate(stale func(*structType) bool) {
+	c.flight.Lock()
+	defer c.flight.Unlock()
+	c.m.Range(func(key, value interface{}) bool {
+		if entry := value.(*cacheEntry); stale(entry.value) {
+			c.remove(entry)
+		}
+		return true
+	})
 }
 
+// set caches the struct with the flight lock held, the least recently used entry is evicted
+// before inserting if the bounded cache is full
 func (c *cache) set(key reflect.Type, value *structType) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	m := c.m.Load().(map[reflect.Type]*structType)
-	nm := make(map[reflect.Type]*structType, len(m)+1)
-	for k, v := range m {
-		nm[k] = v
-	}
-	nm[key] = value
-	c.m.Store(nm)
+	if _, loaded := c.m.Load(key); loaded {
+		return
+	}
+	entry := &cacheEntry{key: key, value: value}
+	if c.maxSize > 0 {
+		for c.lru.Len() >= c.maxSize {
+			c.remove(c.evictee())
+			c.evictions.Add(1)
+		}
+		entry.listed = c.clock.Add(1)
+		entry.stamp.Store(entry.listed)
+		entry.elem = c.lru.PushBack(entry)
+	}
+	c.m.Store(key, entry)
+	c.size.Add(1)
+}
+
+// evictee returns the least recently used entry of the lru, the entries used after they were listed
+// are listed again by their stamps, which are newer than the front, until the front is not used.
+// Every entry is listed again at most once, since the stamps may be written by the hits meanwhile
+func (c *cache) evictee() *cacheEntry {
+	for n := c.lru.Len(); n > 0; n-- {
+		front := c.lru.Front().Value.(*cacheEntry)
+		stamp := front.stamp.Load()
+		if stamp == front.listed {
+			return front
+		}
+		front.listed = stamp
+		// stops at the front at the latest, which is listed by stamp now
+		at := c.lru.Back()
+		for at.Value.(*cacheEntry).listed > stamp {
+			at = at.Prev()
+		}
+		if at != front.elem {
+			c.lru.MoveAfter(front.elem, at)
+		}
+	}
+	return c.lru.Front().Value.(*cacheEntry)
+}
+
+// remove deletes the entry from m and lru with the flight lock held
+func (c *cache) remove(entry *cacheEntry) {
+	if c.m.CompareAndDelete(entry.key, entry) {
+		c.size.Add(-1)
+	}
+	if entry.elem != nil {
+		c.lru.Remove(entry.elem)
+		entry.elem = nil
+	}
+}
+
+// reset removes all of the cached structs, the counters are kept
+func (c *cache) reset() {
+	c.flight.Lock()
+	defer c.flight.Unlock()
+	c.m.Range(func(_, value interface{}) bool {
+		c.remove(value.(*cacheEntry))
+		return true
+	})
+}
+
+func (c *cache) stats() CacheStats {
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Compiles:  c.compiles.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      int(c.size.Load()),
+	}
+}
+
+// Warmup compiles the types before the bindings, e.g. at the startup, so that the first requests do not
+// compile them, the types are pointers to the structs or to the slices, arrays or maps of structs,
+// the nil pointers are enough, like g.Warmup((*Params)(nil))
+func (g *Gbind) Warmup(types ...interface{}) error {
+	for _, v := range types {
+		if _, err := g.compileType(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Warmup compiles the types for the package functions
+func Warmup(types ...interface{}) error {
+	return defaultGbind.Warmup(types...)
+}
+
+// ResetCache removes the compiled structs, they are compiled again by the next binding,
+// e.g. after the execers, transforms or sanitizers used by them are registered again
+func (g *Gbind) ResetCache() {
+	g.localCache.reset()
+}
+
+// CacheStats returns the counters of the cache of the compiled structs
+func (g *Gbind) CacheStats() CacheStats {
+	return g.localCache.stats()
 }
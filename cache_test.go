@@ -0,0 +1,201 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dynamicType creates a struct type like the generic gateways by reflect.StructOf
+func dynamicType(i int) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{{
+		Name: "F" + strconv.Itoa(i),
+		Type: reflect.TypeOf(""),
+		Tag:  reflect.StructTag(`gbind:"http.query.f"`),
+	}})
+}
+
+func TestCacheStats(t *testing.T) {
+	type params struct {
+		Appkey string `gbind:"http.query.appkey"`
+	}
+	g := NewGbind()
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+	for i := 0; i < 3; i++ {
+		p := &params{}
+		_, err := g.Bind(context.Background(), p, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", p.Appkey)
+	}
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Compiles: 1, Size: 1}, g.CacheStats())
+
+	g.ResetCache()
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Compiles: 1}, g.CacheStats())
+	_, err := g.Bind(context.Background(), &params{}, req)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Compiles: 2, Size: 1}, g.CacheStats())
+}
+
+func TestCacheSize(t *testing.T) {
+	g := NewGbind(WithCacheSize(2))
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?f=abc", nil)
+	bind := func(i int) {
+		v := reflect.New(dynamicType(i))
+		_, err := g.Bind(context.Background(), v.Interface(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", v.Elem().Field(0).String())
+	}
+	bind(0)
+	bind(1)
+	bind(0) // 0 is the most recently used
+	bind(2) // 1 is evicted
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3, Compiles: 3, Evictions: 1, Size: 2}, g.CacheStats())
+	bind(0)
+	bind(1)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Compiles: 4, Evictions: 2, Size: 2}, g.CacheStats())
+
+	g.ResetCache()
+	assert.Equal(t, 0, g.CacheStats().Size)
+	bind(0)
+	assert.Equal(t, 1, g.CacheStats().Size)
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(3)
+	types := make([]reflect.Type, 6)
+	for i := range types {
+		types[i] = dynamicType(i)
+	}
+	cached := func() (keys []int) {
+		for i, typ := range types {
+			if _, ok := c.m.Load(typ); ok {
+				keys = append(keys, i)
+			}
+		}
+		return keys
+	}
+	set := func(i int) { c.set(types[i], &structType{}) }
+	hit := func(i int) {
+		_, ok := c.load(types[i])
+		assert.True(t, ok)
+	}
+
+	set(0)
+	set(1)
+	set(2)
+	// the order of the hits is kept between the insertions
+	hit(1)
+	hit(0)
+	hit(2)
+	set(3) // 1 is the least recently used
+	assert.Equal(t, []int{0, 2, 3}, cached())
+	hit(0)
+	set(4) // 2 was used before 3 was inserted
+	assert.Equal(t, []int{0, 3, 4}, cached())
+	hit(4)
+	hit(3)
+	set(5)
+	assert.Equal(t, []int{3, 4, 5}, cached())
+	assert.Equal(t, uint64(3), c.evictions.Load())
+	assert.Equal(t, 3, c.lru.Len())
+
+	c.reset()
+	assert.Empty(t, cached())
+	assert.Equal(t, 0, c.lru.Len())
+	assert.Equal(t, int64(0), c.size.Load())
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	for _, size := range []int{0, 8} {
+		g := NewGbind(WithCacheSize(size))
+		req, _ := http.NewRequest("GET", "http://localhost:8080/?f=abc", nil)
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v := reflect.New(dynamicType(i % 16))
+				_, err := g.Bind(context.Background(), v.Interface(), req)
+				assert.Nil(t, err)
+				if i%8 == 0 {
+					g.ResetCache()
+				}
+			}(i)
+		}
+		wg.Wait()
+		stats := g.CacheStats()
+		assert.Equal(t, uint64(32), stats.Hits+stats.Misses)
+		if size > 0 {
+			assert.LessOrEqual(t, stats.Size, size)
+		}
+	}
+}
+
+func TestCompileOnce(t *testing.T) {
+	g := NewGbind()
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?f=abc", nil)
+	rt := dynamicType(0)
+	var (
+		start = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			v := reflect.New(rt)
+			_, err := g.Bind(context.Background(), v.Interface(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, "abc", v.Elem().Field(0).String())
+		}()
+	}
+	close(start)
+	wg.Wait()
+	stats := g.CacheStats()
+	assert.Equal(t, uint64(1), stats.Compiles)
+	assert.Equal(t, uint64(64), stats.Hits+stats.Misses)
+}
+
+func TestCompileOnceError(t *testing.T) {
+	type params struct {
+		Appkey string `gbind:"http.query.appkey,default_func=unknown"`
+	}
+	g := NewGbind()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := g.compile(&params{})
+			assert.NotNil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, CacheStats{Misses: 16}, g.CacheStats())
+}
+
+func TestWarmup(t *testing.T) {
+	type params struct {
+		Appkey string `gbind:"http.query.appkey"`
+	}
+	g := NewGbind()
+	assert.Nil(t, g.Warmup((*params)(nil), &[]params{}, &map[string]*params{}))
+	assert.Equal(t, CacheStats{Misses: 3, Compiles: 3, Size: 3}, g.CacheStats())
+
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+	p := &params{}
+	_, err := g.Bind(context.Background(), p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", p.Appkey)
+	assert.Equal(t, uint64(1), g.CacheStats().Hits)
+
+	assert.NotNil(t, g.Warmup((*params)(nil), params{}))
+	assert.NotNil(t, g.Warmup((*int)(nil)))
+	assert.Nil(t, Warmup((*params)(nil)))
+}
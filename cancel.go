@@ -0,0 +1,84 @@
+package gbind
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithBindTimeout limits the duration of every binding, e.g. for the slow custom execers,
+// the binding returns context.DeadlineExceeded when the timeout is exceeded, no timeout if d is not positive.
+// A blocking read of the body of the net/http server is not interrupted, bound it by http.Server.ReadTimeout
+func WithBindTimeout(d time.Duration) OptApply {
+	return func(opt *options) {
+		opt.bindTimeout = d
+	}
+}
+
+// contextBody stops reading the body when ctx is done, ctx is checked before every read and after a failed read.
+// The body is closed when ctx is done, which interrupts a blocking read of the bodies like io.Pipe,
+// but the Close of the net/http server body waits for the blocking read, which returns when the data arrives,
+// the connection is closed or the read deadline of the server is exceeded
+type contextBody struct {
+	io.ReadCloser
+	ctx  context.Context
+	stop func() bool
+	err  error
+}
+
+// wrapContextBody wraps the body of req until unwrap is called, nil if ctx can not be done or the body is empty
+func wrapContextBody(ctx context.Context, req *http.Request) *contextBody {
+	if ctx.Done() == nil || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	b := &contextBody{ReadCloser: req.Body, ctx: ctx}
+	b.stop = context.AfterFunc(ctx, func() {
+		b.ReadCloser.Close()
+	})
+	req.Body = b
+	return b
+}
+
+func (b *contextBody) Read(p []byte) (int, error) {
+	if b.err == nil {
+		b.err = b.ctx.Err()
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && b.ctx.Err() != nil {
+		// the body is closed by the cancellation
+		b.err = b.ctx.Err()
+		return n, b.err
+	}
+	return n, err
+}
+
+// unwrap restores the body of req replaced by b
+func (b *contextBody) unwrap(req *http.Request) {
+	b.stop()
+	if req.Body == io.ReadCloser(b) {
+		req.Body = b.ReadCloser
+	}
+}
+
+// detachedContext the values of the context derived during the binding with the timeout or the concurrent execers,
+// it is done by the parent instead of the cancellation when the binding returns
+type detachedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (c *detachedContext) Deadline() (time.Time, bool) {
+	return c.parent.Deadline()
+}
+
+func (c *detachedContext) Done() <-chan struct{} {
+	return c.parent.Done()
+}
+
+func (c *detachedContext) Err() error {
+	return c.parent.Err()
+}
@@ -0,0 +1,198 @@
+package gbind
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepExecer sets the key after sleeping, or returns ctx.Err() if ctx is done before
+type sleepExecer struct {
+	d     time.Duration
+	calls *int
+}
+
+func (s *sleepExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	*s.calls++
+	select {
+	case <-time.After(s.d):
+	case <-ctx.Done():
+		return ctx, ctx.Err()
+	}
+	return context.WithValue(ctx, slowKey("sleep"), "slept"), opt.Set(ctx, value, []string{"slept"})
+}
+
+func (s *sleepExecer) Name() string {
+	return "sleep"
+}
+
+func newSleepGbind(calls *int, opts ...OptApply) *Gbind {
+	g := NewGbind(opts...)
+	g.RegisterBindFunc("sleep", func(values [][]byte) (Execer, error) {
+		d, err := time.ParseDuration(string(values[1]))
+		return &sleepExecer{d: d, calls: calls}, err
+	})
+	return g
+}
+
+func TestBindCancelled(t *testing.T) {
+	type params struct {
+		A string `gbind:"sleep.1ms"`
+	}
+	calls := 0
+	g := newSleepGbind(&calls)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := g.Bind(ctx, &params{}, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestBindTimeout(t *testing.T) {
+	type params struct {
+		A string `gbind:"sleep.1ms"`
+		B string `gbind:"sleep.1h"`
+		C string `gbind:"sleep.1ms"`
+	}
+	calls := 0
+	g := newSleepGbind(&calls, WithBindTimeout(50*time.Millisecond))
+	start := time.Now()
+	p := &params{}
+	_, err := g.Bind(context.Background(), p, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "slept", p.A)
+	assert.Equal(t, "", p.C)
+	// C is not executed after the timeout
+	assert.Equal(t, 2, calls)
+
+	// the context derived by the execers is not done by the timeout
+	type fast struct {
+		A string `gbind:"sleep.1ms"`
+	}
+	ctx, err := g.Bind(context.Background(), &fast{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "slept", ctx.Value(slowKey("sleep")))
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, ctx.Err())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+}
+
+// blockingBody blocks the read until it is closed
+type blockingBody struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func newBlockingBody(prefix string) *blockingBody {
+	return &blockingBody{Reader: strings.NewReader(prefix), closed: make(chan struct{})}
+}
+
+func (b *blockingBody) Read(p []byte) (int, error) {
+	if n, _ := b.Reader.Read(p); n > 0 {
+		return n, nil
+	}
+	<-b.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (b *blockingBody) Close() error {
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+	return nil
+}
+
+func TestBindCancelBody(t *testing.T) {
+	type jsonParams struct {
+		Name string `json:"name"`
+	}
+	type formParams struct {
+		Name string `gbind:"http.form.name"`
+	}
+	for _, c := range []struct {
+		contentType string
+		prefix      string
+		v           interface{}
+	}{
+		{"application/json", `{"name":`, &jsonParams{}},
+		{"application/x-www-form-urlencoded", "name=", &formParams{}},
+	} {
+		body := newBlockingBody(c.prefix)
+		req, _ := http.NewRequest("POST", "http://localhost:8080/", body)
+		req.Header.Set("Content-Type", c.contentType)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := Bind(ctx, c.v, req)
+		cancel()
+		assert.Equal(t, context.DeadlineExceeded, err, c.contentType)
+		assert.Equal(t, body, req.Body, "the body is unwrapped")
+	}
+
+	// the limited body is unwrapped too
+	g := NewGbind(WithMaxBodyBytes(1024))
+	req, _ := http.NewRequest("POST", "http://localhost:8080/", strings.NewReader(`{"name":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	body := req.Body
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &jsonParams{}
+	_, err := g.Bind(ctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", p.Name)
+	assert.Equal(t, body, req.Body)
+}
+
+// TestBindCancelServerBody the read of the server body is not interrupted, the cancellation is returned after it
+func TestBindCancelServerBody(t *testing.T) {
+	type jsonParams struct {
+		Name string `json:"name"`
+	}
+	for _, c := range []struct {
+		readTimeout time.Duration
+		stall       time.Duration
+	}{
+		// returned after the client sends the rest
+		{0, 200 * time.Millisecond},
+		// returned by the read deadline of the server
+		{100 * time.Millisecond, 2 * time.Second},
+	} {
+		done := make(chan error, 1)
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			_, err := Bind(ctx, &jsonParams{}, r)
+			if c.readTimeout == 0 {
+				assert.GreaterOrEqual(t, time.Since(start), c.stall-50*time.Millisecond)
+			} else {
+				assert.Less(t, time.Since(start), c.stall/2)
+			}
+			done <- err
+		}))
+		srv.Config.ReadTimeout = c.readTimeout
+		srv.Start()
+		pr, pw := io.Pipe()
+		go func() {
+			pw.Write([]byte(`{"name":`))
+			time.Sleep(c.stall)
+			pw.Write([]byte(`"abc"}`))
+			pw.Close()
+		}()
+		resp, err := http.Post(srv.URL, "application/json", pr)
+		if err == nil {
+			resp.Body.Close()
+		}
+		assert.Equal(t, context.DeadlineExceeded, <-done, c.readTimeout)
+		srv.Close()
+	}
+}
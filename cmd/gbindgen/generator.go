@@ -0,0 +1,516 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bdjimmy/gbind/internal/gotypes"
+	"github.com/bdjimmy/gbind/internal/tags"
+)
+
+const (
+	// bindTag the tag applied by the generated code
+	bindTag = "gbind"
+	// defaultSplitFlag the default of gbind.WithDefaultSplitFlag
+	defaultSplitFlag = "|"
+)
+
+// transformFuncs the functions of the built-in transforms of tags.Transforms
+var transformFuncs = map[string]string{
+	"trim":  "strings.TrimSpace",
+	"lower": "strings.ToLower",
+	"upper": "strings.ToUpper",
+}
+
+// collectionSeps the separators of the collection formats
+var collectionSeps = map[string]string{
+	"csv":   ",",
+	"ssv":   " ",
+	"pipes": "|",
+}
+
+// alloc a pointer to the nested struct which is allocated before its fields are set
+type alloc struct {
+	expr string
+	typ  string
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]bool
+	buf     *bytes.Buffer
+	// query and form the values of the query and the form are used by the type being generated
+	query bool
+	form  bool
+}
+
+func newGenerator(pkg *types.Package) *generator {
+	return &generator{
+		pkg: pkg,
+		buf: &bytes.Buffer{},
+		imports: map[string]bool{
+			"context":                  true,
+			"net/http":                 true,
+			"github.com/bdjimmy/gbind": true,
+		},
+	}
+}
+
+func (g *generator) p(format string, args ...interface{}) {
+	fmt.Fprintf(g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// importPaths returns the sorted imports, the standard packages are grouped before the others
+func (g *generator) importPaths() (std []string, others []string) {
+	for path := range g.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	return std, others
+}
+
+// qualifier records the imports of the types of the other packages
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+	g.imports[pkg.Path()] = true
+	return pkg.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// generateType generates the BindHTTP method of the named struct type
+func (g *generator) generateType(name string) error {
+	obj := g.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("type %s not found in package %s", name, g.pkg.Path())
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("%s is not a struct", name)
+	}
+
+	// the fields are generated before the head of the method
+	head := g.buf
+	g.buf = &bytes.Buffer{}
+	g.query, g.form = false, false
+	n, err := g.walkStruct(st, name, "v", nil)
+	if err != nil {
+		return err
+	}
+	body := g.buf
+	g.buf = head
+
+	g.p("")
+	g.p("// BindHTTP sets the fields of %s bound by the http sources of the gbind tag, it implements gbind.HTTPBinder", name)
+	g.p("func (v *%s) BindHTTP(ctx context.Context, r *http.Request) error {", name)
+	g.p("if r == nil {")
+	g.p("return gbind.ErrNilRequest")
+	g.p("}")
+	if n == 0 {
+		g.p("return nil")
+		g.p("}")
+		return nil
+	}
+	if g.query {
+		g.p("query := r.URL.Query()")
+	}
+	if g.form {
+		g.p("form, err := gbind.PostForm(ctx, r)")
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+	}
+	g.p("var vs []string")
+	g.buf.Write(body.Bytes())
+	g.p("return nil")
+	g.p("}")
+	return nil
+}
+
+// walkStruct generates the fields of st in the same order as Gbind compiles them,
+// and returns the number of the generated fields
+func (g *generator) walkStruct(st *types.Struct, ns, expr string, allocs []alloc) (int, error) {
+	n := 0
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Embedded() && !f.Exported() {
+			continue
+		}
+		var (
+			fns   = ns + "." + f.Name()
+			fexpr = expr + "." + f.Name()
+			typ   = f.Type()
+			ptr   = false
+		)
+		if p, ok := typ.Underlying().(*types.Pointer); ok {
+			typ, ptr = p.Elem(), true
+		}
+		tag, tagged := reflect.StructTag(st.Tag(i)).Lookup(bindTag)
+		if nested, ok := typ.Underlying().(*types.Struct); ok && !(tagged && gotypes.IsTextUnmarshaler(typ)) {
+			nallocs := allocs
+			if ptr {
+				nallocs = append(append([]alloc{}, allocs...), alloc{expr: fexpr, typ: g.typeString(typ)})
+			}
+			nn, err := g.walkStruct(nested, fns, fexpr, nallocs)
+			if err != nil {
+				return 0, err
+			}
+			n += nn
+			continue
+		}
+		if !tagged {
+			continue
+		}
+		if _, ok := typ.Underlying().(*types.Pointer); ok {
+			return 0, fmt.Errorf("%s: multilevel pointers are not supported", fns)
+		}
+		ft, err := parseTag(tag, typ)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %s", fns, err)
+		}
+		n++
+
+		g.p("")
+		g.p("// %s `%s:%q`", fns, bindTag, tag)
+		g.p("if err := ctx.Err(); err != nil {")
+		g.p("return err")
+		g.p("}")
+		for _, a := range allocs {
+			g.p("if %s == nil {", a.expr)
+			g.p("%s = new(%s)", a.expr, a.typ)
+			g.p("}")
+		}
+		target := fexpr
+		if ptr {
+			g.p("if %s == nil {", fexpr)
+			g.p("%s = new(%s)", fexpr, g.typeString(typ))
+			g.p("}")
+			target = "*" + fexpr
+		}
+		g.values(fns, ft)
+		if err := g.assign(fns, target, typ); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// values sets vs to the values of the field like gbind.DefaultOption, the transforms and the limits
+// are applied to the values of the source, or vs is set to the default values if the source has no value
+func (g *generator) values(ns string, ft *fieldTag) {
+	switch ft.source {
+	case "path":
+		g.p("vs = []string{r.URL.Path}")
+	case "header":
+		g.p("vs = r.Header[%q]", textproto.CanonicalMIMEHeaderKey(ft.param))
+	case "cookie":
+		g.imports["net/url"] = true
+		g.p("vs = nil")
+		g.p("if c, err := r.Cookie(%q); err == nil {", ft.param)
+		g.p("s, _ := url.QueryUnescape(c.Value)")
+		g.p("vs = []string{s}")
+		g.p("}")
+	case "query", "form":
+		values := ft.source
+		if values == "query" {
+			g.query = true
+		} else {
+			g.form = true
+		}
+		switch ft.collection {
+		case "csv", "ssv", "pipes":
+			g.imports["strings"] = true
+			g.p("vs = nil")
+			g.p("for _, s := range %s[%q] {", values, ft.param)
+			g.p("vs = append(vs, strings.Split(s, %q)...)", collectionSeps[ft.collection])
+			g.p("}")
+		case "brackets":
+			g.p("vs = %s[%q]", values, ft.param+"[]")
+		case "indexed":
+			g.p("vs = gbind.IndexedValues(%s, %q)", values, ft.param)
+		default:
+			g.p("vs = %s[%q]", values, ft.param)
+		}
+	}
+
+	if len(ft.steps) == 0 && ft.maxItems == 0 && ft.maxLen == 0 {
+		if ft.defaults != nil {
+			g.p("if len(vs) == 0 {")
+			g.p("vs = %s", stringsLit(ft.defaults))
+			g.p("}")
+		}
+		return
+	}
+	g.p("if len(vs) > 0 {")
+	declared := false
+	for _, step := range ft.steps {
+		g.imports["strings"] = true
+		define := ":="
+		if declared {
+			define = "="
+		}
+		declared = true
+		if step.split != "" {
+			g.p("nvs %s make([]string, 0, len(vs))", define)
+			g.p("for _, s := range vs {")
+			g.p("nvs = append(nvs, strings.Split(s, %q)...)", step.split)
+			g.p("}")
+		} else {
+			call := "s"
+			for _, name := range step.funcs {
+				call = transformFuncs[name] + "(" + call + ")"
+			}
+			g.p("nvs %s make([]string, len(vs))", define)
+			g.p("for i, s := range vs {")
+			g.p("nvs[i] = %s", call)
+			g.p("}")
+		}
+		g.p("vs = nvs")
+	}
+	if ft.maxItems > 0 {
+		g.p("if len(vs) > %d {", ft.maxItems)
+		g.p("return &gbind.LimitError{Limit: gbind.LimitSliceLen, Max: %d, Field: %q}", ft.maxItems, ns)
+		g.p("}")
+	}
+	if ft.maxLen > 0 {
+		g.p("for _, s := range vs {")
+		g.p("if len(s) > %d {", ft.maxLen)
+		g.p("return &gbind.LimitError{Limit: gbind.LimitStringLen, Max: %d, Field: %q}", ft.maxLen, ns)
+		g.p("}")
+		g.p("}")
+	}
+	if ft.defaults != nil {
+		g.p("} else {")
+		g.p("vs = %s", stringsLit(ft.defaults))
+	}
+	g.p("}")
+}
+
+// stringsLit the literal of the string slice
+func stringsLit(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// assign sets the values vs to the target like gbind.TrySet
+func (g *generator) assign(ns, target string, typ types.Type) error {
+	if gotypes.IsDuration(typ) {
+		g.imports["time"] = true
+		g.p("if len(vs) == 1 {")
+		g.p("d, err := time.ParseDuration(vs[0])")
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("%s = d", target)
+		g.p("}")
+		return nil
+	}
+	if gotypes.IsTextUnmarshaler(typ) {
+		g.p("if len(vs) > 0 {")
+		g.unmarshalText(target, "vs[0]")
+		g.p("}")
+		return nil
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		g.p("if len(vs) > 0 {")
+		g.p("s := make(%s, len(vs))", g.typeString(typ))
+		g.p("for i, val := range vs {")
+		if err := g.parse(ns, "s[i]", t.Elem(), "val"); err != nil {
+			return err
+		}
+		g.p("}")
+		g.p("%s = s", target)
+		g.p("}")
+	case *types.Array:
+		g.imports["fmt"] = true
+		g.p("if len(vs) > 0 {")
+		g.p("if len(vs) != %d {", t.Len())
+		// vs is copied so that it does not escape with the literals of the sources and the defaults
+		g.p("return fmt.Errorf(\"%%q is not valid value for %s\", append([]string(nil), vs...))", types.TypeString(typ, (*types.Package).Name))
+		g.p("}")
+		g.p("for i, val := range vs {")
+		if strings.HasPrefix(target, "*") {
+			target = "(" + target + ")"
+		}
+		if err := g.parse(ns, target+"[i]", t.Elem(), "val"); err != nil {
+			return err
+		}
+		g.p("}")
+		g.p("}")
+	default:
+		g.p("if len(vs) > 0 {")
+		if err := g.parse(ns, target, typ, "vs[0]"); err != nil {
+			return err
+		}
+		g.p("}")
+	}
+	return nil
+}
+
+// unmarshalText sets the string src to the target by UnmarshalText like gbind.TrySet
+func (g *generator) unmarshalText(target, src string) {
+	if strings.HasPrefix(target, "*") {
+		target = "(" + target + ")"
+	}
+	g.p("if err := %s.UnmarshalText([]byte(%s)); err != nil {", target, src)
+	g.p("return err")
+	g.p("}")
+}
+
+// parse sets the string src to the target of the basic type or the type implementing encoding.TextUnmarshaler
+func (g *generator) parse(ns, target string, typ types.Type, src string) error {
+	if gotypes.IsTextUnmarshaler(typ) {
+		g.unmarshalText(target, src)
+		return nil
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return fmt.Errorf("%s: type %s is not supported", ns, typ)
+	}
+	conv := g.typeString(typ)
+	info := basic.Info()
+	if info&types.IsString != 0 {
+		if types.Identical(typ, types.Typ[types.String]) {
+			g.p("%s = %s", target, src)
+		} else {
+			g.p("%s = %s(%s)", target, conv, src)
+		}
+		return nil
+	}
+
+	// the parsed type of the gbind functions
+	var parsed types.BasicKind
+	switch {
+	case info&types.IsBoolean != 0:
+		parsed = types.Bool
+		g.p("b, err := gbind.ParseBool(%s)", src)
+	case info&types.IsUnsigned != 0:
+		parsed = types.Uint64
+		g.p("b, err := gbind.ParseUint(%s, %d)", src, gotypes.BitSize(basic.Kind()))
+	case info&types.IsInteger != 0:
+		parsed = types.Int64
+		g.p("b, err := gbind.ParseInt(%s, %d)", src, gotypes.BitSize(basic.Kind()))
+	case info&types.IsFloat != 0:
+		parsed = types.Float64
+		g.p("b, err := gbind.ParseFloat(%s, %d)", src, gotypes.BitSize(basic.Kind()))
+	default:
+		return fmt.Errorf("%s: type %s is not supported", ns, typ)
+	}
+	g.p("if err != nil {")
+	g.p("return err")
+	g.p("}")
+	if types.Identical(typ, types.Typ[parsed]) {
+		g.p("%s = b", target)
+	} else {
+		g.p("%s = %s(b)", target, conv)
+	}
+	return nil
+}
+
+// fieldTag the bind tag of a field parsed like Gbind compiles it with the default options
+type fieldTag struct {
+	// source and param of the http source, e.g. query and appkey of http.query.appkey
+	source string
+	param  string
+	// collection the format of the slice values in query and form
+	collection string
+	// steps the transform pipeline
+	steps []step
+	// defaults the default values, nil if there is no default
+	defaults []string
+	// maxItems and maxLen the limits of max_items= and max_len=
+	maxItems int
+	maxLen   int
+}
+
+// step a split or the consecutive built-in transforms of the pipeline
+type step struct {
+	split string
+	funcs []string
+}
+
+// parseTag parses the bind tag of the field of typ, only the http sources and the built-in transforms are supported
+func parseTag(tag string, typ types.Type) (*fieldTag, error) {
+	source, opts := tags.Split(tag)
+	parts := strings.Split(source, ".")
+	if parts[0] != "http" || len(parts) < 2 {
+		return nil, fmt.Errorf("source %q is not supported, only the http sources are generated", source)
+	}
+	if arity, ok := tags.HTTPArity[parts[1]]; !ok || len(parts) != arity {
+		return nil, fmt.Errorf("invalid source %q", source)
+	}
+	ft := &fieldTag{source: parts[1]}
+	if len(parts) > 2 {
+		ft.param = parts[2]
+	}
+	// the slice fields are collected by the format, the default of gbind.WithCollectionFormat is multi
+	_, isSlice := typ.Underlying().(*types.Slice)
+	if _, ok := typ.Underlying().(*types.Array); ok {
+		isSlice = true
+	}
+	collected := gotypes.IsCollected(typ)
+	if collected {
+		ft.collection = "multi"
+	}
+	for _, o := range opts {
+		switch o.Key {
+		case "default":
+			ft.defaults = strings.Split(o.Value, defaultSplitFlag)
+		case "default_func":
+			return nil, fmt.Errorf("default_func %q is not supported, only the static defaults are generated", o.Value)
+		case "max_items", "max_len":
+			n, err := strconv.Atoi(o.Value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %s %q", o.Key, o.Value)
+			}
+			if o.Key == "max_items" && isSlice {
+				ft.maxItems = n
+			} else if o.Key == "max_len" {
+				ft.maxLen = n
+			}
+		case "collection":
+			if !collected {
+				return nil, fmt.Errorf("collection format %q of the non-slice field", o.Value)
+			}
+			if _, ok := collectionSeps[o.Value]; !ok && o.Value != "multi" && o.Value != "brackets" && o.Value != "indexed" {
+				return nil, fmt.Errorf("unknown collection format %q", o.Value)
+			}
+			ft.collection = o.Value
+		case "split":
+			if o.Value == "" {
+				return nil, fmt.Errorf("empty split")
+			}
+			ft.steps = append(ft.steps, step{split: o.Value})
+		default:
+			if _, ok := transformFuncs[o.Key]; !ok {
+				return nil, fmt.Errorf("transform %q is not supported, only the built-in transforms are generated", o.Key)
+			}
+			if n := len(ft.steps); n > 0 && ft.steps[n-1].split == "" {
+				ft.steps[n-1].funcs = append(ft.steps[n-1].funcs, o.Key)
+			} else {
+				ft.steps = append(ft.steps, step{funcs: []string{o.Key}})
+			}
+		}
+	}
+	return ft, nil
+}
@@ -0,0 +1,121 @@
+// Command gbindgen generates the reflection-free binding of the structs with the gbind tags.
+//
+// For the struct
+//
+//	type Params struct {
+//		Appkey string `gbind:"http.query.appkey,default=abc"`
+//		Uids   []int  `gbind:"http.form.uids"`
+//	}
+//
+// running `gbindgen -type=Params` in the directory of the package creates params_gbind.go,
+// which sets the fields with direct assignments in
+//
+//	func (v *Params) BindHTTP(ctx context.Context, r *http.Request) error
+//
+// Params implements gbind.HTTPBinder, so that Gbind.Bind calls BindHTTP instead of
+// setting the fields with the reflection. Typically it is run by go generate:
+//
+//	//go:generate gbindgen -type=Params
+//
+// The fields implementing encoding.TextUnmarshaler like time.Time are set by UnmarshalText.
+// The generated code reads the query, form, header, cookie and path of the request directly,
+// and applies the collection formats, the built-in transforms, the defaults and the limits
+// of the gbind tag with the default options of Gbind. The other sources, the registered transforms
+// and default_func are not supported. Gbind.Bind binds the struct with the reflection instead of
+// BindHTTP if its options are not the default ones, see gbind.HTTPBinder.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_gbind.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of gbindgen:\n")
+	fmt.Fprintf(os.Stderr, "\tgbindgen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gbindgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	names := strings.Split(*typeNames, ",")
+
+	src, err := generate(dir, names, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(names[0])+"_gbind.go")
+	}
+	if err := os.WriteFile(name, src, 0644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+}
+
+// generate returns the formatted source of the BindHTTP methods of the types in the package of dir
+func generate(dir string, typeNames []string, args []string) ([]byte, error) {
+	// the dependencies are type checked from the source, which does not depend on the export data of the compiler
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages found in %s", len(pkgs), dir)
+	}
+	g := newGenerator(pkgs[0].Types)
+	for _, name := range typeNames {
+		if err := g.generateType(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by \"gbindgen %s\"; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg.Name())
+	buf.WriteString("import (\n")
+	std, others := g.importPaths()
+	for _, path := range std {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString("\n")
+	for _, path := range others {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n")
+	buf.Write(g.buf.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %s\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bdjimmy/gbind/internal/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	// the generated code of internal/gentest is up to date
+	src, err := generate("../../internal/gentest", []string{"Params", "Empty"}, []string{"-type=Params,Empty"})
+	assert.Nil(t, err)
+	expect, err := os.ReadFile("../../internal/gentest/params_gbind.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expect), string(src))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for name, msg := range map[string]string{
+		"Custom":      `Custom.Key: source "simple.key" is not supported, only the http sources are generated`,
+		"Arity":       `Arity.Path: invalid source "http.path.api"`,
+		"Map":         `Map.Labels: type map[string]string is not supported`,
+		"Ptrs":        `Ptrs.Ids: type *int is not supported`,
+		"NotStruct":   `NotStruct is not a struct`,
+		"Unknown":     `type Unknown not found in package`,
+		"DefaultFunc": `DefaultFunc.Since: default_func "now" is not supported, only the static defaults are generated`,
+		"Transform":   `Transform.Name: transform "reverse" is not supported, only the built-in transforms are generated`,
+		"Collection":  `Collection.Id: collection format "csv" of the non-slice field`,
+		"Format":      `Format.Ids: unknown collection format "tsv"`,
+		"Split":       `Split.Ids: empty split`,
+	} {
+		_, err := generate("testdata/bad", []string{name}, nil)
+		if assert.NotNil(t, err, name) {
+			assert.Contains(t, err.Error(), msg)
+		}
+	}
+}
+
+func TestTransformFuncs(t *testing.T) {
+	names := make([]string, 0, len(transformFuncs))
+	for name := range transformFuncs {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, tags.Transforms, names)
+}
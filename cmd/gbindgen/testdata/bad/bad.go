@@ -0,0 +1,39 @@
+package bad
+
+type Custom struct {
+	Key string `gbind:"simple.key"`
+}
+
+type Arity struct {
+	Path string `gbind:"http.path.api"`
+}
+
+type Map struct {
+	Labels map[string]string `gbind:"http.query.labels"`
+}
+
+type Ptrs struct {
+	Ids []*int `gbind:"http.query.ids"`
+}
+
+type NotStruct int
+
+type DefaultFunc struct {
+	Since string `gbind:"http.query.since,default_func=now"`
+}
+
+type Transform struct {
+	Name string `gbind:"http.query.name,reverse"`
+}
+
+type Collection struct {
+	Id int `gbind:"http.query.id,collection=csv"`
+}
+
+type Format struct {
+	Ids []int `gbind:"http.query.ids,collection=tsv"`
+}
+
+type Split struct {
+	Ids []int `gbind:"http.query.ids,split=,trim"`
+}
@@ -0,0 +1,13 @@
+// Command gbindvet checks the gbind, err_msg and validate tags, it is run by go vet:
+//
+//	go vet -vettool=$(which gbindvet) ./...
+package main
+
+import (
+	"github.com/bdjimmy/gbind/gbindvet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(gbindvet.Analyzer)
+}
@@ -0,0 +1,96 @@
+package gbind
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CollectionFormat the format of the slice values in query and form,
+// it can be selected by `gbind:"http.query.uids,collection=csv"` or WithCollectionFormat
+type CollectionFormat string
+
+const (
+	// CollectionMulti repeated keys, uids=1&uids=2
+	CollectionMulti CollectionFormat = "multi"
+	// CollectionCSV comma separated values, uids=1,2
+	CollectionCSV CollectionFormat = "csv"
+	// CollectionSSV space separated values, uids=1%202
+	CollectionSSV CollectionFormat = "ssv"
+	// CollectionPipes pipe separated values, uids=1|2
+	CollectionPipes CollectionFormat = "pipes"
+	// CollectionBrackets repeated keys with brackets, uids[]=1&uids[]=2
+	CollectionBrackets CollectionFormat = "brackets"
+	// CollectionIndexed indexed keys, uids[0]=1&uids[1]=2
+	CollectionIndexed CollectionFormat = "indexed"
+)
+
+var collectionSeps = map[CollectionFormat]string{
+	CollectionCSV:   ",",
+	CollectionSSV:   " ",
+	CollectionPipes: "|",
+}
+
+func isCollectionFormat(f CollectionFormat) bool {
+	switch f {
+	case CollectionMulti, CollectionCSV, CollectionSSV, CollectionPipes, CollectionBrackets, CollectionIndexed:
+		return true
+	}
+	return false
+}
+
+// collectValues gets the values of key from values according to the format
+func collectValues(values url.Values, key string, format CollectionFormat) []string {
+	switch format {
+	case CollectionCSV, CollectionSSV, CollectionPipes:
+		vs := values[key]
+		if len(vs) == 0 {
+			return vs
+		}
+		nvs := make([]string, 0, len(vs))
+		for _, v := range vs {
+			nvs = append(nvs, strings.Split(v, collectionSeps[format])...)
+		}
+		return nvs
+	case CollectionBrackets:
+		return values[key+"[]"]
+	case CollectionIndexed:
+		return indexedValues(values, key)
+	default:
+		return values[key]
+	}
+}
+
+// indexedValues collects uids[0]=1&uids[1]=2 in the order of the index
+func indexedValues(values url.Values, key string) []string {
+	type indexed struct {
+		i  int
+		vs []string
+	}
+	var (
+		prefix = key + "["
+		items  []indexed
+		n      int
+	)
+	for k, vs := range values {
+		if !strings.HasPrefix(k, prefix) || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		i, err := strconv.Atoi(k[len(prefix) : len(k)-1])
+		if err != nil || i < 0 {
+			continue
+		}
+		items = append(items, indexed{i: i, vs: vs})
+		n += len(vs)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	sort.Slice(items, func(a, b int) bool { return items[a].i < items[b].i })
+	vs := make([]string, 0, n)
+	for _, item := range items {
+		vs = append(vs, item.vs...)
+	}
+	return vs
+}
@@ -0,0 +1,109 @@
+package gbind
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectValues(t *testing.T) {
+	for testName, tt := range map[string]struct {
+		query  string
+		format CollectionFormat
+		expect []string
+	}{
+		"multi": {
+			"uids=1&uids=2", CollectionMulti, []string{"1", "2"},
+		},
+		"csv": {
+			"uids=1,2,3", CollectionCSV, []string{"1", "2", "3"},
+		},
+		"ssv": {
+			"uids=1%202", CollectionSSV, []string{"1", "2"},
+		},
+		"pipes": {
+			"uids=1|2", CollectionPipes, []string{"1", "2"},
+		},
+		"brackets": {
+			"uids[]=1&uids[]=2&uids=3", CollectionBrackets, []string{"1", "2"},
+		},
+		"indexed": {
+			"uids[1]=2&uids[0]=1&uids[10]=3&uids[x]=4", CollectionIndexed, []string{"1", "2", "3"},
+		},
+		"indexed-missing": {
+			"uids=1", CollectionIndexed, nil,
+		},
+		"csv-missing": {
+			"", CollectionCSV, nil,
+		},
+	} {
+		values, err := url.ParseQuery(tt.query)
+		assert.Nil(t, err, testName)
+		assert.Equal(t, tt.expect, collectValues(values, "uids", tt.format), testName)
+	}
+}
+
+func TestCollectionFormat(t *testing.T) {
+	// per field
+	{
+		type Foo struct {
+			Uids []int    `gbind:"http.query.uids,collection=csv"`
+			Tags []string `gbind:"http.form.tags,collection=brackets"`
+			Name string   `gbind:"http.query.name"`
+		}
+		f := &Foo{}
+		req := newReq().
+			addQueryParam("uids", "1,2,3").
+			addQueryParam("name", "a,b").
+			addFormParam("tags[]", "x").
+			addFormParam("tags[]", "y").r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 3}, f.Uids)
+		assert.Equal(t, []string{"x", "y"}, f.Tags)
+		assert.Equal(t, "a,b", f.Name)
+	}
+
+	// invalid
+	{
+		type Unknown struct {
+			Uids []int `gbind:"http.query.uids,collection=tsv"`
+		}
+		_, err := Bind(context.Background(), &Unknown{}, newReq().r())
+		assert.EqualError(t, err, `gbind: unknown collection format "tsv" of Unknown.Uids`)
+
+		type NotSlice struct {
+			Name string `gbind:"http.query.name,collection=csv"`
+		}
+		_, err = Bind(context.Background(), &NotSlice{}, newReq().r())
+		assert.EqualError(t, err, `gbind: collection format "csv" of NotSlice.Name which is bound as a single value`)
+
+		type Unmarshaler struct {
+			IP net.IP `gbind:"http.query.ip,collection=csv"`
+		}
+		_, err = Bind(context.Background(), &Unmarshaler{}, newReq().r())
+		assert.EqualError(t, err, `gbind: collection format "csv" of Unmarshaler.IP which is bound as a single value`)
+	}
+
+	// gbind option
+	{
+		g := NewGbind(WithCollectionFormat(CollectionIndexed))
+		type Foo struct {
+			Uids []int `gbind:"http.query.uids"`
+			Ids  []int `gbind:"http.query.ids,collection=multi"`
+		}
+		f := &Foo{}
+		req := newReq().
+			addQueryParam("uids[1]", "2").
+			addQueryParam("uids[0]", "1").
+			addQueryParam("ids", "3").
+			addQueryParam("ids", "4").r()
+		_, err := g.Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, f.Uids)
+		assert.Equal(t, []int{3, 4}, f.Ids)
+	}
+}
@@ -0,0 +1,57 @@
+package gbind
+
+import (
+	"reflect"
+
+	"github.com/bdjimmy/gbind/internal/compiled"
+)
+
+func init() {
+	compiled.Compile = compileStruct
+}
+
+// compileStruct exposes the compiled struct to the other packages of the module
+func compileStruct(gi interface{}, v interface{}) (*compiled.Struct, error) {
+	g, _ := gi.(*Gbind)
+	if g == nil {
+		g = defaultGbind
+	}
+	st, err := g.compileType(v)
+	if err != nil {
+		return nil, err
+	}
+	cs := &compiled.Struct{
+		Fields:      make([]compiled.Field, 0, len(st.fieldList)),
+		JSON:        st.hasJSONTag,
+		BindTag:     g.options.bindTagName,
+		ValidateTag: g.options.validateTagName,
+	}
+	for _, f := range st.fieldList {
+		cf := compiled.Field{
+			Namespace:        f.namespace,
+			StructField:      f.structField,
+			Param:            execerParam(f.excer),
+			HasDefault:       f.defaultOpt.IsDefaultExists,
+			Default:          f.defaultOpt.DefaultValue,
+			DefaultSplitFlag: f.defaultOpt.DefaultSplitFlag,
+			CollectionFormat: string(f.defaultOpt.CollectionFormat),
+		}
+		if f.excer != nil {
+			cf.Source = f.excer.Name()
+		}
+		cs.Fields = append(cs.Fields, cf)
+	}
+	return cs, nil
+}
+
+// compileType compiles the type of v, a nil pointer is enough
+func (g *Gbind) compileType(v interface{}) (*structType, error) {
+	rt := reflect.TypeOf(v)
+	if rt == nil || rt.Kind() != reflect.Ptr {
+		return nil, e("cannot compile non-pointer %v", rt)
+	}
+	if err := g.checkValid(reflect.New(rt.Elem())); err != nil {
+		return nil, err
+	}
+	return g.compile(v)
+}
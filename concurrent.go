@@ -0,0 +1,205 @@
+package gbind
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// ConcurrentExecer is implemented by the execers which can run concurrently with the other execers,
+// e.g. the custom execers querying the caches or decoding the JWT
+type ConcurrentExecer interface {
+	Execer
+	// Concurrent reports whether Exec is safe to run concurrently, it must not modify the shared state
+	// of the binding, e.g. the other fields of the struct
+	Concurrent() bool
+}
+
+// WithConcurrentExecers runs the execers whose Concurrent() is true in at most n goroutines per binding,
+// concurrently with the other execers, the execers run one by one if n is not positive, which is the default.
+//
+// NOTES:
+// - a concurrent execer receives the context derived by the other execers of the previous fields
+// - when a field fails, the execers of the later fields are cancelled or skipped, the error of the first
+// failed field in the order of the struct is returned, the same as running them one by one
+// - the contexts derived by the concurrent execers are merged into the returned context, the later field
+// wins if they set the same key
+func WithConcurrentExecers(n int) OptApply {
+	return func(opt *options) {
+		opt.concurrentExecers = n
+	}
+}
+
+func isConcurrent(ex Execer) bool {
+	cex, ok := ex.(ConcurrentExecer)
+	return ok && cex.Concurrent()
+}
+
+// concurrentJob a concurrent execer with the context derived by the previous fields,
+// which is not done by the cancellation of the execers running one by one
+type concurrentJob struct {
+	index int
+	ctx   context.Context
+}
+
+// concurrentRun the state of the fields shared by the workers and the execers running one by one
+type concurrentRun struct {
+	lock sync.Mutex
+	// failed the index of the first failed field, the number of the fields if none fails
+	failed int
+	err    error
+	// current the index of the field run one by one
+	current      int
+	cancels      []context.CancelFunc
+	cancelSerial context.CancelFunc
+}
+
+// enter reports whether the field i runs, it does not if a previous field has failed
+func (r *concurrentRun) enter(i int) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.current = i
+	return i < r.failed
+}
+
+// start returns the context of the concurrent field i, false if a previous field has failed
+func (r *concurrentRun) start(ctx context.Context, i int) (context.Context, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if i >= r.failed {
+		return nil, false
+	}
+	ctx, r.cancels[i] = context.WithCancel(ctx)
+	return ctx, true
+}
+
+// fail records the error of the field i and cancels the execers of the later fields
+func (r *concurrentRun) fail(i int, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if i >= r.failed {
+		return
+	}
+	r.failed, r.err = i, err
+	for _, cancel := range r.cancels[i+1:] {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	if r.current > i {
+		r.cancelSerial()
+	}
+}
+
+func (r *concurrentRun) cancel() {
+	for _, cancel := range r.cancels {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	r.cancelSerial()
+}
+
+// execConcurrent runs the concurrent execers in the workers and the others in the order of the struct
+func (sv *structType) execConcurrent(ctx context.Context, rv reflect.Value, data interface{}, workers int) (context.Context, error) {
+	var (
+		n      = len(sv.execFields)
+		values = make([]reflect.Value, n)
+		ctxs   = make([]context.Context, n)
+		jobs   = make(chan concurrentJob, len(sv.concurrentFields))
+		run    = &concurrentRun{failed: n, cancels: make([]context.CancelFunc, n)}
+		wg     sync.WaitGroup
+	)
+	// the nil pointers of the struct are allocated before the workers start
+	for i, f := range sv.execFields {
+		values[i] = fieldByIndexs(rv, f.index)
+	}
+	if workers > len(sv.concurrentFields) {
+		workers = len(sv.concurrentFields)
+	}
+	sctx, cancel := context.WithCancel(ctx)
+	run.cancelSerial = cancel
+	defer run.cancel()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				cctx, ok := run.start(job.ctx, job.index)
+				if !ok {
+					continue
+				}
+				if err := cctx.Err(); err != nil {
+					run.fail(job.index, err)
+					continue
+				}
+				f := sv.execFields[job.index]
+				dctx, err := f.excer.Exec(cctx, values[job.index], data, &f.defaultOpt)
+				if err != nil {
+					run.fail(job.index, err)
+					continue
+				}
+				if dctx != cctx {
+					ctxs[job.index] = dctx
+				}
+			}
+		}()
+	}
+	cur := sctx
+	for i, f := range sv.execFields {
+		if !run.enter(i) {
+			break
+		}
+		if f.concurrent {
+			jobs <- concurrentJob{index: i, ctx: &detachedContext{Context: cur, parent: ctx}}
+			continue
+		}
+		if err := cur.Err(); err != nil {
+			run.fail(i, err)
+			break
+		}
+		dctx, err := f.excer.Exec(cur, values[i], data, &f.defaultOpt)
+		if err != nil {
+			run.fail(i, err)
+			break
+		}
+		cur = dctx
+	}
+	close(jobs)
+	wg.Wait()
+	if run.err != nil {
+		return ctx, run.err
+	}
+
+	var derived []context.Context
+	for _, c := range ctxs {
+		if c != nil {
+			derived = append(derived, c)
+		}
+	}
+	if len(derived) > 0 {
+		cur = &mergedContext{Context: cur, derived: derived}
+	}
+	if cur == sctx {
+		return ctx, nil
+	}
+	// the contexts derived by the execers are not done by the cancellation of the fields after the binding
+	return &detachedContext{Context: cur, parent: ctx}, nil
+}
+
+// mergedContext looks up the values of the contexts derived by the concurrent execers in the reverse
+// order of the fields, before the context of the other execers
+type mergedContext struct {
+	context.Context
+	derived []context.Context
+}
+
+func (c *mergedContext) Value(key interface{}) interface{} {
+	for i := len(c.derived) - 1; i >= 0; i-- {
+		if v := c.derived[i].Value(key); v != nil {
+			return v
+		}
+	}
+	return c.Context.Value(key)
+}
@@ -0,0 +1,252 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slowKey string
+
+// slowExecer sets the key after all of the execers sharing the barrier have started
+type slowExecer struct {
+	key     string
+	barrier *sync.WaitGroup
+}
+
+func (s *slowExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	if strings.HasPrefix(s.key, "err") {
+		return ctx, errors.New(s.key)
+	}
+	if s.barrier != nil {
+		s.barrier.Done()
+		s.barrier.Wait()
+	}
+	return context.WithValue(ctx, slowKey(s.key), s.key), opt.Set(ctx, value, []string{s.key})
+}
+
+func (s *slowExecer) Name() string {
+	return "slow"
+}
+
+func (s *slowExecer) Concurrent() bool {
+	return true
+}
+
+func newSlowGbind(barrier *sync.WaitGroup, opts ...OptApply) *Gbind {
+	g := NewGbind(opts...)
+	g.RegisterBindFunc("slow", func(values [][]byte) (Execer, error) {
+		return &slowExecer{key: string(values[1]), barrier: barrier}, nil
+	})
+	return g
+}
+
+func TestConcurrentExecers(t *testing.T) {
+	type nested struct {
+		C string `gbind:"slow.c"`
+	}
+	type params struct {
+		A      string `gbind:"slow.a"`
+		Appkey string `gbind:"http.query.appkey"`
+		B      string `gbind:"slow.b"`
+		Nested *nested
+	}
+	var barrier sync.WaitGroup
+	barrier.Add(3)
+	g := newSlowGbind(&barrier, WithConcurrentExecers(3))
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+
+	done := make(chan struct{})
+	p := &params{}
+	var (
+		ctx context.Context
+		err error
+	)
+	go func() {
+		defer close(done)
+		ctx, err = g.Bind(context.Background(), p, req)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the execers do not run concurrently")
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, &params{A: "a", Appkey: "abc", B: "b", Nested: &nested{C: "c"}}, p)
+	for _, key := range []string{"a", "b", "c"} {
+		assert.Equal(t, key, ctx.Value(slowKey(key)))
+	}
+	assert.Equal(t, req, requestMeta(ctx).Request())
+}
+
+func TestConcurrentExecersErrors(t *testing.T) {
+	type params struct {
+		A      string `gbind:"slow.a"`
+		Err1   string `gbind:"slow.err1"`
+		Appkey int    `gbind:"http.query.appkey"`
+		Err2   string `gbind:"slow.err2"`
+	}
+	g := newSlowGbind(nil, WithConcurrentExecers(2))
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+	for i := 0; i < 20; i++ {
+		_, err := g.Bind(context.Background(), &params{}, req)
+		assert.EqualError(t, err, "err1")
+	}
+
+	req, _ = http.NewRequest("GET", "http://localhost:8080/?appkey=1", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := g.Bind(ctx, &params{}, req)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestConcurrentExecersDisabled(t *testing.T) {
+	type params struct {
+		A string `gbind:"slow.a"`
+		B string `gbind:"slow.b"`
+	}
+	g := newSlowGbind(nil)
+	p := &params{}
+	ctx, err := g.Bind(context.Background(), p, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &params{A: "a", B: "b"}, p)
+	assert.Equal(t, "a", ctx.Value(slowKey("a")))
+	assert.Equal(t, "b", ctx.Value(slowKey("b")))
+}
+
+// blockExecer blocks until ctx is done
+type blockExecer struct {
+	started   chan struct{}
+	cancelled chan error
+}
+
+func (b *blockExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	b.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		b.cancelled <- ctx.Err()
+		return ctx, ctx.Err()
+	case <-time.After(5 * time.Second):
+		b.cancelled <- nil
+		return ctx, nil
+	}
+}
+
+func (b *blockExecer) Name() string {
+	return "block"
+}
+
+func (b *blockExecer) Concurrent() bool {
+	return true
+}
+
+// failExecer fails after blockExecer has started
+type failExecer struct {
+	started chan struct{}
+}
+
+func (f *failExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	<-f.started
+	return ctx, errors.New("fail")
+}
+
+func (f *failExecer) Name() string {
+	return "fail"
+}
+
+func (f *failExecer) Concurrent() bool {
+	return true
+}
+
+func TestConcurrentExecersCancel(t *testing.T) {
+	type params struct {
+		Fail  string `gbind:"fail.a"`
+		Block string `gbind:"block.a"`
+	}
+	type serialParams struct {
+		Block  string `gbind:"block.a"`
+		Appkey int    `gbind:"http.query.appkey"`
+		Err1   string `gbind:"slow.err1"`
+	}
+	var (
+		started = make(chan struct{}, 1)
+		block   = &blockExecer{started: started, cancelled: make(chan error, 1)}
+		fail    = &failExecer{started: started}
+	)
+	g := newSlowGbind(nil, WithConcurrentExecers(2))
+	g.RegisterBindFunc("block", func(values [][]byte) (Execer, error) {
+		return block, nil
+	})
+	g.RegisterBindFunc("fail", func(values [][]byte) (Execer, error) {
+		return fail, nil
+	})
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+
+	// the execers of the later fields are cancelled
+	_, err := g.Bind(context.Background(), &params{}, req)
+	assert.EqualError(t, err, "fail")
+	assert.Equal(t, context.Canceled, <-block.cancelled)
+
+	// the execers of the previous fields are not cancelled, their errors are returned
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = g.Bind(ctx, &serialParams{}, req)
+	<-started
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, context.DeadlineExceeded, <-block.cancelled)
+}
+
+// serialExecer sets a value of the context for the later execers
+type serialExecer struct{}
+
+func (serialExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	return context.WithValue(ctx, slowKey("serial"), "from-serial"), nil
+}
+
+func (serialExecer) Name() string {
+	return "serial"
+}
+
+// readExecer sets the value of the context set by serialExecer
+type readExecer struct{}
+
+func (readExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	v, _ := ctx.Value(slowKey("serial")).(string)
+	return ctx, opt.Set(ctx, value, []string{v})
+}
+
+func (readExecer) Name() string {
+	return "read"
+}
+
+func (readExecer) Concurrent() bool {
+	return true
+}
+
+func TestConcurrentExecersContext(t *testing.T) {
+	type params struct {
+		Before string `gbind:"read.a"`
+		Serial string `gbind:"serial.a"`
+		After  string `gbind:"read.a"`
+	}
+	g := NewGbind(WithConcurrentExecers(2))
+	g.RegisterBindFunc("serial", func(values [][]byte) (Execer, error) {
+		return serialExecer{}, nil
+	})
+	g.RegisterBindFunc("read", func(values [][]byte) (Execer, error) {
+		return readExecer{}, nil
+	})
+	p := &params{}
+	ctx, err := g.Bind(context.Background(), p, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &params{After: "from-serial"}, p)
+	assert.Equal(t, "from-serial", ctx.Value(slowKey("serial")))
+	assert.Nil(t, ctx.Err())
+}
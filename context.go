@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -17,6 +20,61 @@ type httpMetaData struct {
 	request    *http.Request
 	queryCache url.Values
 	formCache  url.Values
+	// limits of the Gbind which created the context, nil if not limited
+	limits *limits
+	// formErr the LimitError of the form parsing
+	formErr error
+	// scratch the values returned by queryValues, reused by the next call
+	scratch []string
+}
+
+// maxScratch the maximum capacity of the scratch kept by the pooled httpMetaData
+const maxScratch = 64
+
+var httpMetaPool = sync.Pool{
+	New: func() interface{} {
+		return &httpMetaData{}
+	},
+}
+
+// bindContext the context.Context passed to the execers, default_func and validators of a binding,
+// it is not pooled because the user code may keep it, only its httpMetaData is pooled
+type bindContext struct {
+	context.Context
+	// meta nil after the release, the context kept by the user code does not see the next binding
+	meta atomic.Pointer[httpMetaData]
+}
+
+func newBindContext(ctx context.Context, req *http.Request) *bindContext {
+	md := httpMetaPool.Get().(*httpMetaData)
+	md.request = req
+	c := &bindContext{Context: ctx}
+	c.meta.Store(md)
+	return c
+}
+
+// release detaches the httpMetaData and puts it back to the pool
+func (c *bindContext) release() {
+	md := c.meta.Swap(nil)
+	if md == nil {
+		return
+	}
+	scratch := md.scratch
+	clear(scratch[:cap(scratch)])
+	if cap(scratch) > maxScratch {
+		scratch = nil
+	}
+	*md = httpMetaData{scratch: scratch[:0]}
+	httpMetaPool.Put(md)
+}
+
+func (c *bindContext) Value(key interface{}) interface{} {
+	if key == (metaKey{}) {
+		if md := c.meta.Load(); md != nil {
+			return md
+		}
+	}
+	return c.Context.Value(key)
 }
 
 func newHTTPContext(ctx context.Context, req *http.Request) context.Context {
@@ -50,17 +108,79 @@ func (hm *httpMetaData) initQueryCache() {
 
 func (hm *httpMetaData) initFormCache() {
 	if hm.formCache == nil {
-		hm.request.ParseMultipartForm(defaultMultipartMemory)
+		maxMemory := defaultMultipartMemory
+		if hm.limits != nil && hm.limits.maxMultipartMemory > 0 {
+			maxMemory = hm.limits.maxMultipartMemory
+		}
+		var files *multipartFiles
+		if hm.limits != nil {
+			files = hm.limits.countMultipartFiles(hm.request)
+		}
+		if files != nil {
+			files.parseForm(hm.request, maxMemory)
+		} else {
+			hm.request.ParseMultipartForm(maxMemory)
+		}
 		hm.formCache = hm.request.PostForm
+		if hm.formCache == nil {
+			hm.formCache = url.Values{}
+		}
+		if files != nil && files.limitErr() != nil {
+			hm.formErr = files.limitErr()
+			return
+		}
+		hm.formErr = bodyErr(hm.request)
 	}
 }
 
-func (hm *httpMetaData) getFormArray(key string) (values []string) {
+func (hm *httpMetaData) getFormArray(key string, format CollectionFormat) (values []string, err error) {
 	hm.initFormCache()
-	return hm.formCache[key]
+	return collectValues(hm.formCache, key, format), hm.formErr
 }
 
-func (hm *httpMetaData) getQueryArray(key string) (values []string) {
+func (hm *httpMetaData) getQueryArray(key string, format CollectionFormat) (values []string) {
 	hm.initQueryCache()
-	return hm.queryCache[key]
+	return collectValues(hm.queryCache, key, format)
+}
+
+// queryArray is the same as getQueryArray, but the repeated keys are scanned from the raw query
+// without parsing the whole query, the returned slice is reused by the next call
+func (hm *httpMetaData) queryArray(key string, format CollectionFormat) []string {
+	if hm.queryCache != nil || hm.request == nil || (format != "" && format != CollectionMulti) {
+		return hm.getQueryArray(key, format)
+	}
+	return hm.queryValues(key)
+}
+
+// queryValues parses the raw query like url.ParseQuery, the values which are not escaped
+// are the substrings of the raw query
+func (hm *httpMetaData) queryValues(key string) []string {
+	hm.scratch = hm.scratch[:0]
+	query := hm.request.URL.RawQuery
+	for query != "" {
+		var pair string
+		pair, query, _ = strings.Cut(query, "&")
+		if pair == "" || strings.Contains(pair, ";") {
+			continue
+		}
+		k, v, _ := strings.Cut(pair, "=")
+		if k, ok := queryUnescape(k); !ok || k != key {
+			continue
+		}
+		v, ok := queryUnescape(v)
+		if !ok {
+			continue
+		}
+		hm.scratch = append(hm.scratch, v)
+	}
+	return hm.scratch
+}
+
+// queryUnescape unescapes s only if it is escaped, ok is false if s is invalid
+func queryUnescape(s string) (string, bool) {
+	if !strings.ContainsAny(s, "%+") {
+		return s, true
+	}
+	s, err := url.QueryUnescape(s)
+	return s, err == nil
 }
@@ -0,0 +1,137 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryValues(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"a=1",
+		"a=1&a=2&b=3",
+		"a=&a",
+		"a=1&&a=2",
+		"a=%31+2&a%3D=3",
+		"%61=1&a=2",
+		"a=%zz&a=1",
+		"%zz=1&a=2",
+		"a=1;a=2&a=3",
+		"b=1&c=2",
+	} {
+		req, _ := http.NewRequest("GET", "http://localhost:8080/?"+query, nil)
+		want, _ := url.ParseQuery(query)
+		md := &httpMetaData{request: req}
+		for _, key := range []string{"a", "a=", "b"} {
+			vs := md.queryValues(key)
+			if len(want[key]) == 0 {
+				assert.Empty(t, vs, query)
+			} else {
+				assert.Equal(t, want[key], vs, query)
+			}
+		}
+	}
+}
+
+type derivedKey struct{}
+
+type derivedExecer struct{}
+
+func (derivedExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	return context.WithValue(ctx, derivedKey{}, "derived"), nil
+}
+
+func (derivedExecer) Name() string {
+	return "derived"
+}
+
+func TestBindContext(t *testing.T) {
+	type params struct {
+		Appkey string `gbind:"http.query.appkey"`
+	}
+	type derived struct {
+		Appkey string `gbind:"http.query.appkey"`
+		Key    string `gbind:"derived"`
+	}
+	g := NewGbind()
+	g.RegisterBindFunc("derived", func(values [][]byte) (Execer, error) {
+		return derivedExecer{}, nil
+	})
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+
+	// the context with the pooled httpMetaData is not returned
+	ctx := context.Background()
+	p := &params{}
+	rctx, err := g.Bind(ctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", p.Appkey)
+	assert.Equal(t, ctx, rctx)
+
+	// the context derived by the execers is returned with the request
+	d := &derived{}
+	rctx, err = g.Bind(ctx, d, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", d.Appkey)
+	assert.Equal(t, "derived", rctx.Value(derivedKey{}))
+	assert.Equal(t, req, requestMeta(rctx).Request())
+
+	// the binding with the derived context shares its state
+	p = &params{}
+	_, err = g.Bind(rctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", p.Appkey)
+}
+
+type userKey struct{}
+
+// keepExecer keeps the first context passed to it, like the user code which uses it after the binding
+type keepExecer struct {
+	ctx *context.Context
+}
+
+func (k keepExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	if *k.ctx == nil {
+		*k.ctx = ctx
+	}
+	return ctx, nil
+}
+
+func (keepExecer) Name() string {
+	return "keep"
+}
+
+func TestBindContextKept(t *testing.T) {
+	type params struct {
+		Appkey string `gbind:"http.query.appkey"`
+		Keep   string `gbind:"keep"`
+	}
+	var kept context.Context
+	g := NewGbind()
+	g.RegisterBindFunc("keep", func(values [][]byte) (Execer, error) {
+		return keepExecer{ctx: &kept}, nil
+	})
+	ctx := context.WithValue(context.Background(), userKey{}, "user")
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+	_, err := g.Bind(ctx, &params{}, req)
+	assert.Nil(t, err)
+
+	// the next binding does not reuse the kept context
+	other, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=def", nil)
+	p := &params{}
+	_, err = g.Bind(context.Background(), p, other)
+	assert.Nil(t, err)
+	assert.Equal(t, "def", p.Appkey)
+	assert.Equal(t, "user", kept.Value(userKey{}))
+	assert.Nil(t, kept.Value(metaKey{}))
+
+	// the kept context binds with a new httpMetaData
+	p = &params{}
+	_, err = g.Bind(kept, p, other)
+	assert.Nil(t, err)
+	assert.Equal(t, "def", p.Appkey)
+}
@@ -0,0 +1,93 @@
+package gbind
+
+import "reflect"
+
+// Plan the binding plan of a struct compiled by Gbind, it is a read-only copy of the compiled struct
+type Plan struct {
+	// Type the type bound to, e.g. *api.Params
+	Type string `json:"type"`
+	// JSON whether the body is decoded as json
+	JSON bool `json:"json,omitempty"`
+	// Elem whether the elements of a slice, array or map of structs are bound
+	Elem bool `json:"elem,omitempty"`
+	// Fields the fields in the order of the struct
+	Fields []FieldPlan `json:"fields"`
+}
+
+// FieldPlan the binding plan of a field
+type FieldPlan struct {
+	// Namespace the namespace of the field, e.g. Params.Uids
+	Namespace string `json:"namespace"`
+	// Type the Go type of the field, e.g. []int
+	Type string `json:"type"`
+	// Source the Name() of the execer, e.g. http.query, empty if the field is not bound by the bind tag
+	Source string `json:"source,omitempty"`
+	// Param the name of the param of the http source, e.g. uids of http.query.uids
+	Param string `json:"param,omitempty"`
+	// HasDefault and Default the default= of the bind tag
+	HasDefault bool   `json:"has_default,omitempty"`
+	Default    string `json:"default,omitempty"`
+	// ErrMsg the err_msg of the field, LocaleErrMsgs the localized err_msg by the locales
+	ErrMsg        string            `json:"err_msg,omitempty"`
+	LocaleErrMsgs map[string]string `json:"locale_err_msgs,omitempty"`
+	// Validate the validate rules of the field
+	Validate string `json:"validate,omitempty"`
+}
+
+// Describe returns the binding plan of v by the options of the package functions
+func Describe(v interface{}) (*Plan, error) {
+	return defaultGbind.Describe(v)
+}
+
+// Describe returns the binding plan of v, which lists where each field is bound from, its default,
+// err_msg and validate rules, v is a pointer to the struct or a pointer to a slice, array or map of structs,
+// a nil pointer is enough
+func (g *Gbind) Describe(v interface{}) (*Plan, error) {
+	st, err := g.compileType(v)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{
+		Type:   reflect.TypeOf(v).String(),
+		JSON:   st.hasJSONTag,
+		Elem:   st.elem,
+		Fields: make([]FieldPlan, 0, len(st.fieldList)),
+	}
+	for _, f := range st.fieldList {
+		fp := FieldPlan{
+			Namespace:  f.namespace,
+			Type:       f.structField.Type.String(),
+			Param:      execerParam(f.excer),
+			HasDefault: f.defaultOpt.IsDefaultExists,
+			Default:    f.defaultOpt.DefaultValue,
+			ErrMsg:     st.errMap[f.namespace],
+			Validate:   f.structField.Tag.Get(g.options.validateTagName),
+		}
+		if f.excer != nil {
+			fp.Source = f.excer.Name()
+		}
+		if m := st.localeErrMap[f.namespace]; len(m) > 0 {
+			fp.LocaleErrMsgs = make(map[string]string, len(m))
+			for locale, msg := range m {
+				fp.LocaleErrMsgs[locale] = msg
+			}
+		}
+		plan.Fields = append(plan.Fields, fp)
+	}
+	return plan, nil
+}
+
+// execerParam the name of the param of the http execers, empty for the others
+func execerParam(ex Execer) string {
+	switch ex := ex.(type) {
+	case *httpQueryExcer:
+		return ex.param
+	case *httpFormExcer:
+		return ex.param
+	case *httpHeadExcer:
+		return ex.param
+	case *httpCookieExcer:
+		return ex.param
+	}
+	return ""
+}
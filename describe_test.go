@@ -0,0 +1,63 @@
+package gbind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describeParams struct {
+	API    string `gbind:"http.path"`
+	Appkey string `gbind:"http.query.appkey,default=abc" validate:"required" err_msg:"appkey is required" err_msg_zh:"appkey必填"`
+	Uids   []int  `gbind:"http.form.uids"`
+	Host   string `gbind:"http.header.Host"`
+	Page   struct {
+		Token string `gbind:"http.cookie.Token"`
+	}
+	Name string `json:"name" validate:"max=8"`
+}
+
+func TestDescribe(t *testing.T) {
+	var p *describeParams
+	plan, err := Describe(p)
+	assert.Nil(t, err)
+	assert.Equal(t, &Plan{
+		Type: "*gbind.describeParams",
+		JSON: true,
+		Fields: []FieldPlan{
+			{Namespace: "describeParams.API", Type: "string", Source: "http.path"},
+			{
+				Namespace: "describeParams.Appkey", Type: "string", Source: "http.query", Param: "appkey",
+				HasDefault: true, Default: "abc", ErrMsg: "appkey is required",
+				LocaleErrMsgs: map[string]string{"zh": "appkey必填"}, Validate: "required",
+			},
+			{Namespace: "describeParams.Uids", Type: "[]int", Source: "http.form", Param: "uids"},
+			{Namespace: "describeParams.Host", Type: "string", Source: "http.head", Param: "Host"},
+			{Namespace: "describeParams.Page.Token", Type: "string", Source: "http.cookie", Param: "Token"},
+			{Namespace: "describeParams.Name", Type: "string", Validate: "max=8"},
+		},
+	}, plan)
+
+	// the plan is a copy
+	plan.Fields[1].LocaleErrMsgs["zh"] = "changed"
+	plan, err = Describe(p)
+	assert.Nil(t, err)
+	assert.Equal(t, "appkey必填", plan.Fields[1].LocaleErrMsgs["zh"])
+}
+
+func TestDescribeOptions(t *testing.T) {
+	type params struct {
+		Page int `bind:"http.query.page,default=1" check:"min=1" msg:"invalid page"`
+	}
+	g := NewGbind(WithBindTag("bind"), WithValidateTag("check"), WithErrTag("msg"))
+	plan, err := g.Describe(&[]params{})
+	assert.Nil(t, err)
+	assert.True(t, plan.Elem)
+	assert.Equal(t, []FieldPlan{{
+		Namespace: "params.Page", Type: "int", Source: "http.query", Param: "page",
+		HasDefault: true, Default: "1", ErrMsg: "invalid page", Validate: "min=1",
+	}}, plan.Fields)
+
+	_, err = g.Describe(params{})
+	assert.NotNil(t, err)
+}
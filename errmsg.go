@@ -0,0 +1,53 @@
+package gbind
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bdjimmy/gbind/internal/tags"
+	"github.com/go-playground/validator/v10"
+)
+
+// resolveErrMsg resolves the err_msg for the failed rule of fe.
+//
+// The err_msg can be a template with the placeholders {field}, {tag}, {param} and {value},
+// e.g. `err_msg:"{field} must be at most {param}, got {value}"`,
+// or messages per rule, e.g. `err_msg:"required=please login;max=too long"`,
+// it returns false if none of the rules matches.
+func resolveErrMsg(msg string, fe validator.FieldError) (string, bool) {
+	if rules, ok := parseRuleMsgs(msg); ok {
+		if msg, ok = rules[fe.Tag()]; !ok {
+			if msg, ok = rules[fe.ActualTag()]; !ok {
+				return "", false
+			}
+		}
+	}
+	if !strings.Contains(msg, "{") {
+		return msg, true
+	}
+	return strings.NewReplacer(
+		"{field}", fe.Field(),
+		"{tag}", fe.Tag(),
+		"{param}", fe.Param(),
+		"{value}", fmt.Sprint(fe.Value()),
+	).Replace(msg), true
+}
+
+// parseRuleMsgs parses `required=please login;max=too long`,
+// the msg is not per rule unless every part of it starts with a rule name and "="
+func parseRuleMsgs(msg string) (map[string]string, bool) {
+	if !strings.Contains(msg, "=") {
+		return nil, false
+	}
+	parts := strings.Split(msg, ";")
+	rules := make(map[string]string, len(parts))
+	for _, part := range parts {
+		rule, text := head(part, "=")
+		rule = strings.TrimSpace(rule)
+		if !tags.IsRuleName(rule) || len(rule) == len(part) {
+			return nil, false
+		}
+		rules[rule] = text
+	}
+	return rules, true
+}
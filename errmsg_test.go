@@ -0,0 +1,56 @@
+package gbind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRuleMsgs(t *testing.T) {
+	for msg, expect := range map[string]map[string]string{
+		"please login":                   nil,
+		"required=please login;max=long": {"required": "please login", "max": "long"},
+		"required=please login":          {"required": "please login"},
+		"a=b;please login":               nil,
+		"1 + 1 = 2":                      nil,
+	} {
+		rules, ok := parseRuleMsgs(msg)
+		assert.Equal(t, expect != nil, ok, msg)
+		assert.Equal(t, expect, rules, msg)
+	}
+}
+
+func TestTemplateErrMsg(t *testing.T) {
+	// template
+	{
+		type Foo struct {
+			Size int `gbind:"http.query.size" validate:"max=10" err_msg:"{field} must be at most {param}, got {value}"`
+		}
+		_, err := BindWithValidate(context.Background(), &Foo{}, newReq().addQueryParam("size", "11").r())
+		assert.NotNil(t, err)
+		assert.Equal(t, "Size must be at most 10, got 11", err.Error())
+	}
+
+	// per rule
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name" validate:"required,max=3" err_msg:"required=please input the name;max=the name is longer than {param}"`
+		}
+		_, err := BindWithValidate(context.Background(), &Foo{}, newReq().r())
+		assert.Equal(t, "please input the name", err.Error())
+
+		_, err = BindWithValidate(context.Background(), &Foo{}, newReq().addQueryParam("name", "abcd").r())
+		assert.Equal(t, "the name is longer than 3", err.Error())
+	}
+
+	// the rule does not match
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name" validate:"max=3" err_msg:"required=please input the name"`
+		}
+		_, err := BindWithValidate(context.Background(), &Foo{}, newReq().addQueryParam("name", "abcd").r())
+		assert.NotNil(t, err)
+		assert.NotEqual(t, "please input the name", err.Error())
+	}
+}
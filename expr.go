@@ -22,26 +22,26 @@ type Execer interface {
 
 // execerFactory save all of the excer generators
 type execerFactory struct {
-	m map[string]NewExecer
+	m *registry[NewExecer]
 }
 
 // newexecerFactory save all of the excer generators
 func newexecerFactory() *execerFactory {
 	return &execerFactory{
-		m: map[string]NewExecer{},
+		m: newRegistry(map[string]NewExecer{}),
 	}
 }
 
 // regitster your own implemented generator
 func (ef *execerFactory) regitster(name string, excerFunc NewExecer) *execerFactory {
-	ef.m[name] = excerFunc
+	ef.m.set(name, excerFunc)
 	return ef
 }
 
 // getExecer get an execer
 func (ef *execerFactory) getExecer(value []byte) (execer Execer, err error) {
 	var values = bytes.Split(value, dot)
-	newExecer, ok := ef.m[SliceToString(values[0])]
+	newExecer, ok := ef.m.get(SliceToString(values[0]))
 	if !ok {
 		return nil, fmt.Errorf("syntax error: not support source %s", values[0])
 	}
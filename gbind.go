@@ -5,18 +5,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/bdjimmy/gbind/internal/tags"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
 var (
 	// uids string `gbind:"http.query.uid,default=1|2|3" err_msg:"uids'count should gte 1" validate:"gte=1"`
-	defaultBindTag   = "gbind"
-	defaultErrTag    = "err_msg"
-	defaultSplitFlag = "|"
+	defaultBindTag     = "gbind"
+	defaultErrTag      = "err_msg"
+	defaultValidateTag = "validate"
+	defaultSanitizeTag = "sanitize"
+	defaultSplitFlag   = "|"
 )
 
 // Gbind contains the gbind settings and cache
@@ -28,7 +37,17 @@ type Gbind struct {
 	// tag execers facotry
 	tagExcers *execerFactory
 	// validator
-	validator *defaultValidator
+	validator StructValidator
+	// value transforms used by the bind tag
+	transforms *registry[TransformFunc]
+	// default value generators used by the bind tag
+	defaultFuncs *registry[DefaultFunc]
+	// sanitizers used by the sanitize tag
+	sanitizers *registry[SanitizeFunc]
+	// version the version of the registries, increased by every registration
+	version atomic.Uint64
+	// transformsReplaced a built-in transform is replaced by RegisterTransform
+	transformsReplaced atomic.Bool
 }
 
 type options struct {
@@ -36,11 +55,39 @@ type options struct {
 	bindTagName string
 	// Err tag name being used
 	errTagName string
+	// Sanitize tag name being used
+	sanitizeTagName string
 	// defaultSplitFlag for split default value
 	defaultSplitFlag string
+	// collectionFormat the default format of the slice values in query and form
+	collectionFormat CollectionFormat
+	// validator used by BindWithValidate, defaultValidator if nil
+	validator StructValidator
+	// Validate tag name being used by the default validator
+	validateTagName string
+	// setups of the default validator
+	validateSetups []ValidateSetup
+	// translator of the validation messages, nil if not localized
+	translator *ut.UniversalTranslator
+	// limits of the binding
+	limits limits
 	// useNumberForJSON causes the Decoder to unmarshal a number into an interface{} as a
 	// Number instead of as a float64.
 	useNumberForJSON bool
+	// disallowUnknownFields rejects the unknown keys of the json body
+	disallowUnknownFields bool
+	// disallowTrailingData rejects the data after the first json value
+	disallowTrailingData bool
+	// disallowUnknownParams rejects the query and form keys which no field binds
+	disallowUnknownParams bool
+	// disallowUnknownOptions rejects the options of the bind tag which are not transforms
+	disallowUnknownOptions bool
+	// cacheSize the maximum number of the cached types, unbounded if it is not positive
+	cacheSize int
+	// concurrentExecers the maximum number of the goroutines running the concurrent execers of a binding
+	concurrentExecers int
+	// bindTimeout the timeout of every binding, no timeout if it is not positive
+	bindTimeout time.Duration
 }
 
 // OptApply modify the default option
@@ -60,6 +107,13 @@ func WithErrTag(tag string) OptApply {
 	}
 }
 
+// WithSanitizeTag allows you to change the sanitize tag name used in structs
+func WithSanitizeTag(tag string) OptApply {
+	return func(opt *options) {
+		opt.sanitizeTagName = tag
+	}
+}
+
 // WithDefaultSplitFlag allows you to change the splitFlag used in structs
 func WithDefaultSplitFlag(flag string) OptApply {
 	return func(opt *options) {
@@ -67,6 +121,100 @@ func WithDefaultSplitFlag(flag string) OptApply {
 	}
 }
 
+// WithCollectionFormat allows you to change the default format of the slice values in query and form
+func WithCollectionFormat(format CollectionFormat) OptApply {
+	return func(opt *options) {
+		opt.collectionFormat = format
+	}
+}
+
+// WithValidator allows you to replace the go-playground validator with your own StructValidator,
+// it receives the context.Context of the binding if it implements ContextStructValidator
+func WithValidator(v StructValidator) OptApply {
+	return func(opt *options) {
+		opt.validator = v
+	}
+}
+
+// WithValidateTag allows you to change the validate tag name used by the default validator
+func WithValidateTag(tag string) OptApply {
+	return func(opt *options) {
+		opt.validateTagName = tag
+	}
+}
+
+// WithValidateSetup allows you to configure the default go-playground validator once per Gbind,
+// the setups are applied in order when the validator is initialized
+//
+// NOTES:
+// - it has no effect if the validator has been replaced by WithValidator
+func WithValidateSetup(setup ValidateSetup) OptApply {
+	return func(opt *options) {
+		opt.validateSetups = append(opt.validateSetups, setup)
+	}
+}
+
+// WithStructValidation registers a StructLevelFunc against the types of the default validator
+func WithStructValidation(fn validator.StructLevelFunc, types ...interface{}) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		v.RegisterStructValidation(fn, types...)
+		return nil
+	})
+}
+
+// WithValidationAlias registers a mapping of a single validation tag that
+// defines a common or complex set of validation(s) of the default validator
+func WithValidationAlias(alias, tags string) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		v.RegisterAlias(alias, tags)
+		return nil
+	})
+}
+
+// WithValidationTagNameFunc registers a function to get alternate names for StructFields of the default validator,
+// err_msg is still looked up by the struct field names
+func WithValidationTagNameFunc(fn validator.TagNameFunc) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		v.RegisterTagNameFunc(fn)
+		return nil
+	})
+}
+
+// WithValidationCustomTypeFunc registers a CustomTypeFunc against the types of the default validator
+func WithValidationCustomTypeFunc(fn validator.CustomTypeFunc, types ...interface{}) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		v.RegisterCustomTypeFunc(fn, types...)
+		return nil
+	})
+}
+
+// WithValidation registers a validation with the given tag of the default validator
+func WithValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		return v.RegisterValidation(tag, fn, callValidationEvenIfNull...)
+	})
+}
+
+// WithValidationTranslation registers translations against the provided tag of the default validator
+func WithValidationTranslation(tag string, trans ut.Translator, registerFn validator.RegisterTranslationsFunc, translationFn validator.TranslationFunc) OptApply {
+	return WithValidateSetup(func(v *validator.Validate) error {
+		return v.RegisterTranslation(tag, trans, registerFn, translationFn)
+	})
+}
+
+// WithTranslator allows you to localize the validation messages, the locale of the binding is
+// selected by ContextWithLocale or the Accept-Language header, falling back to the fallback of uni.
+// The built-in translations of the default validator are registered for en, zh and zh_Hant_TW.
+//
+// NOTES:
+// - err_msg_<locale> is preferred to err_msg, e.g. `err_msg_zh:"请先登录" err_msg:"please login"`
+func WithTranslator(uni *ut.UniversalTranslator) OptApply {
+	return func(opt *options) {
+		opt.translator = uni
+		opt.validateSetups = append(opt.validateSetups, registerDefaultTranslations(uni))
+	}
+}
+
 // WithUseNumberForJSON allows you to change the Decoder to unmarshal a number into an interface{} as a
 // Number instead of as a float64
 func WithUseNumberForJSON(use bool) OptApply {
@@ -76,7 +224,7 @@ func WithUseNumberForJSON(use bool) OptApply {
 }
 
 // Helper gbind so users can use the functions directly from the package
-var defaultGbind = NewGbind()
+var defaultGbind = NewGbind(WithValidator(pkgValidator{}))
 
 // NewGbind returns a new instance of 'Gbind' with sane defaults.
 func NewGbind(opts ...OptApply) *Gbind {
@@ -84,16 +232,28 @@ func NewGbind(opts ...OptApply) *Gbind {
 		options: &options{
 			bindTagName:      defaultBindTag,
 			errTagName:       defaultErrTag,
+			sanitizeTagName:  defaultSanitizeTag,
 			defaultSplitFlag: defaultSplitFlag,
+			collectionFormat: CollectionMulti,
+			validateTagName:  defaultValidateTag,
 			useNumberForJSON: false,
 		},
-		localCache: newCache(),
-		tagExcers:  newexecerFactory(),
-		validator:  &defaultValidator{},
+		tagExcers:    newexecerFactory(),
+		transforms:   newRegistry(newTransforms()),
+		defaultFuncs: newRegistry(map[string]DefaultFunc{}),
+		sanitizers:   newRegistry(newSanitizers()),
 	}
 	for _, apply := range opts {
 		apply(g.options)
 	}
+	g.localCache = newCache(g.options.cacheSize)
+	g.validator = g.options.validator
+	if g.validator == nil {
+		g.validator = &defaultValidator{
+			tagName: g.options.validateTagName,
+			setups:  append([]ValidateSetup{registerRequestValidations}, g.options.validateSetups...),
+		}
+	}
 	g.tagExcers.regitster("http", newHTTPExecer)
 	return g
 }
@@ -113,17 +273,96 @@ func BindWithValidate(ctx context.Context, v interface{}, data interface{}) (con
 }
 
 // RegisterBindFunc adds a bind Excer with the given name
+//
+// NOTES:
+// - if the name already exists, the previous Excer will be replaced.
+// - it is safe to register while binding, the structs using the name are compiled again by the next binding
 func (g *Gbind) RegisterBindFunc(name string, fn NewExecer) {
 	g.tagExcers.regitster(name, fn)
+	g.registered(registryExecers, name)
+}
+
+// RegisterTransform adds a value transform with the given name, it can be used
+// in the bind tag like `gbind:"http.query.name,trim,lower"`
+//
+// NOTES:
+// - if the name already exists, the previous transform will be replaced.
+// - the transforms are applied between source extraction and TrySet, in the order of the tag
+// - it is safe to register while binding, the structs using the name are compiled again by the next binding
+func (g *Gbind) RegisterTransform(name string, fn TransformFunc) {
+	if slices.Contains(tags.Transforms, name) {
+		g.transformsReplaced.Store(true)
+	}
+	g.transforms.set(name, fn)
+	g.registered(registryTransforms, name)
+}
+
+// RegisterDefaultFunc adds a default value generator with the given name, it can be used
+// in the bind tag like `gbind:"http.query.since,default_func=now"`
+//
+// NOTES:
+// - if the name already exists, the previous function will be replaced.
+// - the function is called only when the source has no value, and takes precedence over default=
+// - it is safe to register while binding, the structs using the name are compiled again by the next binding
+func (g *Gbind) RegisterDefaultFunc(name string, fn DefaultFunc) {
+	g.defaultFuncs.set(name, fn)
+	g.registered(registryDefaultFuncs, name)
+}
+
+// RegisterSanitizer adds a sanitizer with the given name, it can be used
+// in the sanitize tag like `sanitize:"trim,strip_html,clamp=1 100"`
+//
+// NOTES:
+// - if the name already exists, the previous sanitizer will be replaced.
+// - the sanitizers run after all of the fields are bound and before the validation
+// - it is safe to register while binding, the structs using the name are compiled again by the next binding
+func (g *Gbind) RegisterSanitizer(name string, fn SanitizeFunc) {
+	g.sanitizers.set(name, fn)
+	g.registered(registrySanitizers, name)
 }
 
 // RegisterCustomValidation adds a validation with the given tag
 //
 // NOTES:
 // - if the key already exists, the previous validation function will be replaced.
-// - this method is not thread-safe it is intended that these all be registered prior to any validation
+// - it is safe to register while validating, the validations wait for the registration
+// - it returns an error if the validator has been replaced by WithValidator
 func (g *Gbind) RegisterCustomValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) error {
-	return g.validator.registerCustomValidation(tag, fn, callValidationEvenIfNull...)
+	v, err := g.defaultValidator()
+	if err != nil {
+		return err
+	}
+	return v.registerCustomValidation(tag, fn, callValidationEvenIfNull...)
+}
+
+// RegisterCustomValidationCtx does the same as RegisterCustomValidation,
+// but fn receives the context.Context of the binding for request-scoped rules
+func (g *Gbind) RegisterCustomValidationCtx(tag string, fn validator.FuncCtx, callValidationEvenIfNull ...bool) error {
+	v, err := g.defaultValidator()
+	if err != nil {
+		return err
+	}
+	return v.registerCustomValidationCtx(tag, fn, callValidationEvenIfNull...)
+}
+
+// RegisterRequestValidation adds a validation receiving the facts of the request being bound,
+// e.g. `validate:"required_if_method=POST"`, which can not be done by the fields of the struct.
+// The built-in required_if_method, required_with_query, required_without_query, required_with_header
+// and required_without_header are registered by default.
+//
+// NOTES:
+// - if the key already exists, the previous validation function will be replaced.
+// - it is safe to register while validating, the validations wait for the registration
+func (g *Gbind) RegisterRequestValidation(tag string, fn RequestValidationFunc, callValidationEvenIfNull ...bool) error {
+	return g.RegisterCustomValidationCtx(tag, wrapRequestValidation(fn), callValidationEvenIfNull...)
+}
+
+func (g *Gbind) defaultValidator() (*defaultValidator, error) {
+	v, ok := g.validator.(*defaultValidator)
+	if !ok {
+		return nil, e("the validator %T is not the default validator", g.validator)
+	}
+	return v, nil
 }
 
 // Bind parses the data interface and stores the result
@@ -145,28 +384,97 @@ func (g *Gbind) bind(ctx context.Context, v interface{}, data interface{}, valid
 	if err := g.checkValid(rv); err != nil {
 		return ctx, err
 	}
+	if err := ctx.Err(); err != nil {
+		return ctx, err
+	}
 	st, err := g.compile(v)
 	if err != nil {
 		return ctx, err
 	}
+	if g.options.bindTimeout <= 0 {
+		return g.bindRequest(ctx, st, rv, data, validate)
+	}
+	tctx, cancel := context.WithTimeout(ctx, g.options.bindTimeout)
+	defer cancel()
+	bctx, err := g.bindRequest(tctx, st, rv, data, validate)
+	if bctx == tctx {
+		return ctx, err
+	}
+	// the context derived by the execers is not done by the timeout after the binding
+	return &detachedContext{Context: bctx, parent: ctx}, err
+}
+
+// bindRequest binds with the pooled httpMetaData if data is a http request
+func (g *Gbind) bindRequest(ctx context.Context, st *structType, rv reflect.Value, data interface{}, validate bool) (context.Context, error) {
+	req, ok := data.(*http.Request)
+	if !ok || req == nil {
+		return g.bindStruct(ctx, st, rv, data, validate)
+	}
+	if body := wrapContextBody(ctx, req); body != nil {
+		defer body.unwrap(req)
+	}
+	if ctx.Value(metaKey{}) != nil {
+		return g.bindStruct(ctx, st, rv, data, validate)
+	}
+	// the pooled httpMetaData is released unless the execers derive the context from it
+	bctx := newBindContext(ctx, req)
+	rctx, err := g.bindStruct(bctx, st, rv, data, validate)
+	if rctx != context.Context(bctx) {
+		return rctx, err
+	}
+	bctx.release()
+	return ctx, err
+}
+
+func (g *Gbind) bindStruct(ctx context.Context, st *structType, rv reflect.Value, data interface{}, validate bool) (context.Context, error) {
+	v := rv.Interface()
+	var err error
+	if req, ok := data.(*http.Request); ok && req != nil && g.options.limits != (limits{}) {
+		if body := g.options.limits.limitBody(req); body != nil {
+			defer body.unwrap(req)
+		}
+		ctx = newHTTPContext(ctx, req)
+		mustContextHTTPMeta(ctx).limits = &g.options.limits
+	}
 	// special case
 	if st.hasJSONTag {
 		err := st.parseJSON(data, v)
 		if err != nil {
 			return ctx, err
 		}
+		if err = ctx.Err(); err != nil {
+			return ctx, err
+		}
 	}
-	for _, f := range st.fields {
-		if f.excer == nil {
-			continue
+	if b, req, ok := httpBinder(v, data); ok && !st.elem && st.httpBinder {
+		ctx, err = bindHTTP(ctx, b, req)
+	} else {
+		ctx, err = st.exec(ctx, rv, data)
+	}
+	if err != nil {
+		return ctx, err
+	}
+	if g.options.disallowUnknownParams && !st.elem {
+		if req, ok := data.(*http.Request); ok && req != nil {
+			ctx = newHTTPContext(ctx, req)
+			if err = st.checkParams(mustContextHTTPMeta(ctx)); err != nil {
+				return ctx, err
+			}
 		}
-		ctx, err = f.excer.Exec(ctx, fieldByIndexs(rv, f.index), data, &f.defaultOpt)
-		if err != nil {
+	}
+	if len(st.sanitizeFields) > 0 {
+		if err = st.sanitize(rv); err != nil {
 			return ctx, err
 		}
 	}
+	if err = ctx.Err(); err != nil {
+		return ctx, err
+	}
 	if validate {
-		err = g.errMsg(st, g.validator.ValidateStruct(rv.Interface()))
+		if req, ok := data.(*http.Request); ok {
+			ctx = newHTTPContext(ctx, req)
+		}
+		err = g.errMsg(ctx, data, st, validateStruct(ctx, g.validator, rv.Interface()))
 	}
 	return ctx, err
 }
@@ -176,16 +484,32 @@ func (g *Gbind) compile(value interface{}) (*structType, error) {
 	if st, ok := g.localCache.get(rt); ok {
 		return st, nil
 	}
+	// the concurrent bindings of the same type on a cold cache wait for a single compilation
+	return g.localCache.do(rt, g.compileStruct)
+}
+
+// compileStruct traverses the type rt, which is a pointer to the struct or to a slice, array or map of structs
+func (g *Gbind) compileStruct(rt reflect.Type) (*structType, error) {
 	st := &structType{
-		gbind:      g,
-		hasJSONTag: false,
-		fields:     map[string]*fieldInfo{},
-		errMap:     map[string]string{},
-	}
-	if err := st.deepTraverse(rt.Elem(), reflect.StructField{}, rt.Elem().Name(), []int{}); err != nil {
+		gbind:        g,
+		version:      g.version.Load(),
+		hasJSONTag:   false,
+		fields:       map[string]*fieldInfo{},
+		errMap:       map[string]string{},
+		localeErrMap: map[string]map[string]string{},
+		queryParams:  knownParams{},
+		formParams:   knownParams{},
+		httpBinder:   g.options.generatedOptions(),
+	}
+	elem := rt.Elem()
+	if isContainer(elem) {
+		// bind to a slice, array or map of structs, only the elements are traversed
+		st.elem = true
+		elem = deref(elem.Elem())
+	}
+	if err := st.deepTraverse(elem, reflect.StructField{}, elem.Name(), []int{}); err != nil {
 		return nil, err
 	}
-	g.localCache.set(rt, st)
 	return st, nil
 }
 
@@ -200,17 +524,59 @@ func (g *Gbind) checkValid(rv reflect.Value) error {
 	if rv.IsNil() {
 		return e("cannot bind to a nil value of %q", rv.Type())
 	}
-	if rv.Elem().Type().Kind() != reflect.Struct {
-		return e("binding must be a struct pointer")
+	if rt := rv.Elem().Type(); rt.Kind() != reflect.Struct &&
+		!(isContainer(rt) && deref(rt.Elem()).Kind() == reflect.Struct) {
+		return e("binding must be a struct pointer or a pointer to a slice, array or map of structs")
 	}
 	return nil
 }
 
-func (g *Gbind) errMsg(st *structType, err error) error {
-	if errs, ok := err.(validator.ValidationErrors); ok {
+// errMsg replaces the validation errors with the resolved err_msg of the first matched field,
+// the localized err_msg_<locale> is preferred, and it is translated if WithTranslator is set
+func (g *Gbind) errMsg(ctx context.Context, data interface{}, st *structType, err error) error {
+	var locales []string
+	if len(st.localeErrMap) > 0 || g.options.translator != nil {
+		locales = bindLocales(ctx, data)
+	}
+	return g.resolveErr(st, locales, err)
+}
+
+func (g *Gbind) resolveErr(st *structType, locales []string, err error) error {
+	switch errs := err.(type) {
+	case ElemValidateErrors:
+		nerrs := make(ElemValidateErrors, len(errs))
+		for i, e := range errs {
+			nerrs[i] = ElemValidateError{Index: e.Index, Err: g.resolveErr(st, locales, e.Err)}
+		}
+		return nerrs
+	case validator.ValidationErrors:
+		if len(errs) == 0 {
+			return err
+		}
 		for _, e := range errs {
-			if msg, ok := st.errMap[e.Namespace()]; ok {
-				return errors.New(msg)
+			// Req.Items[2].Qty => Req.Items.Qty
+			ns := trimIndexes(e.StructNamespace())
+			if m, ok := st.localeErrMap[ns]; ok {
+				for _, locale := range locales {
+					if msg, ok := m[locale]; ok {
+						if msg, ok = resolveErrMsg(msg, e); ok {
+							return errors.New(msg)
+						}
+					}
+				}
+			}
+			if msg, ok := st.errMap[ns]; ok {
+				if msg, ok = resolveErrMsg(msg, e); ok {
+					return errors.New(msg)
+				}
+			}
+		}
+		if g.options.translator != nil {
+			trans, _ := g.options.translator.FindTranslator(locales...)
+			return &TranslatedError{
+				Message: errs[0].Translate(trans),
+				Locale:  trans.Locale(),
+				errs:    errs,
 			}
 		}
 	}
@@ -220,8 +586,31 @@ func (g *Gbind) errMsg(st *structType, err error) error {
 type structType struct {
 	gbind      *Gbind
 	hasJSONTag bool
-	fields     map[string]*fieldInfo
-	errMap     map[string]string
+	// elem the binding is a slice, array or map of the struct
+	elem   bool
+	fields map[string]*fieldInfo
+	// fieldList the fields in the order of the struct
+	fieldList []*fieldInfo
+	// structFields the nested structs in the order of the struct
+	structFields []*fieldInfo
+	errMap       map[string]string
+	// localized err_msg, namespace => locale => message
+	localeErrMap map[string]map[string]string
+	// fields with the sanitize tag
+	sanitizeFields []*fieldInfo
+	// params bound by the http.query and http.form fields
+	queryParams knownParams
+	formParams  knownParams
+	// execFields the fields with the excer in the order of the struct
+	execFields []*fieldInfo
+	// concurrentFields the indexes of execFields whose excer can run concurrently
+	concurrentFields []int
+	// deps the names of the registries referenced by the tags
+	deps map[dependency]bool
+	// version the version of the registries when compiled
+	version uint64
+	// httpBinder the HTTPBinder generated by gbindgen applies the same bind tags, see HTTPBinder
+	httpBinder bool
 }
 
 type fieldInfo struct {
@@ -230,6 +619,30 @@ type fieldInfo struct {
 	structField reflect.StructField
 	excer       Execer
 	defaultOpt  DefaultOption
+	sanitizers  []sanitizer
+	// concurrent the excer is a ConcurrentExecer which can run concurrently
+	concurrent bool
+}
+
+// exec sets the fields bound by the gbind tag with the execers
+func (sv *structType) exec(ctx context.Context, rv reflect.Value, data interface{}) (context.Context, error) {
+	if sv.elem {
+		return ctx, nil
+	}
+	if n := sv.gbind.options.concurrentExecers; n > 0 && len(sv.concurrentFields) > 0 {
+		return sv.execConcurrent(ctx, rv, data, n)
+	}
+	var err error
+	for _, f := range sv.execFields {
+		if err = ctx.Err(); err != nil {
+			return ctx, err
+		}
+		ctx, err = f.excer.Exec(ctx, fieldByIndexs(rv, f.index), data, &f.defaultOpt)
+		if err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
 }
 
 func fieldByIndexs(v reflect.Value, indexs []int) reflect.Value {
@@ -257,6 +670,10 @@ func (sv *structType) deepTraverse(rt reflect.Type, field reflect.StructField, n
 	case reflect.Ptr:
 		return sv.traversePtr(rt, field, ns, index)
 	case reflect.Struct:
+		if _, ok := field.Tag.Lookup(sv.gbind.options.bindTagName); ok && isTextUnmarshaler(rt) {
+			// bound as a value by UnmarshalText, e.g. time.Time
+			return sv.traverseField(rt, field, ns, index)
+		}
 		return sv.traverseStruct(rt, field, ns, index)
 	default:
 		return sv.traverseField(rt, field, ns, index)
@@ -269,8 +686,16 @@ func (sv *structType) traversePtr(rt reflect.Type, field reflect.StructField, ns
 
 func (sv *structType) traverseStruct(rt reflect.Type, field reflect.StructField, ns string, index []int) error {
 	ns = namespace(field, ns)
+	if field.Name != "" {
+		sv.structFields = append(sv.structFields, &fieldInfo{
+			namespace:   ns,
+			index:       index,
+			structField: field,
+		})
+	}
 	for i := 0; i < rt.NumField(); i++ {
-		if err := sv.deepTraverse(rt.Field(i).Type, rt.Field(i), ns, append(index, i)); err != nil {
+		// the full slice expression copies the index, the fields do not share the backing array
+		if err := sv.deepTraverse(rt.Field(i).Type, rt.Field(i), ns, append(index[:len(index):len(index)], i)); err != nil {
 			return err
 		}
 	}
@@ -286,8 +711,9 @@ func (sv *structType) traverseField(rt reflect.Type, field reflect.StructField,
 	}
 
 	// err tag
-	if v, ok := field.Tag.Lookup(sv.gbind.options.errTagName); ok {
-		sv.errMap[ns] = v
+	sv.errTags(field, ns)
+	if isContainer(rt) {
+		sv.traverseElemErrTags(rt.Elem(), ns, map[reflect.Type]bool{})
 	}
 
 	fInfo := &fieldInfo{
@@ -297,38 +723,185 @@ func (sv *structType) traverseField(rt reflect.Type, field reflect.StructField,
 		excer:       nil,
 		defaultOpt: DefaultOption{
 			DefaultSplitFlag: sv.gbind.options.defaultSplitFlag,
+			namespace:        ns,
+			maxItems:         sv.gbind.options.limits.maxSliceLen,
+			maxLen:           sv.gbind.options.limits.maxStringLen,
 		},
 	}
 
+	kind := rt.Kind()
+	collected := (kind == reflect.Slice || kind == reflect.Array) && !isTextUnmarshaler(rt)
+	if collected {
+		fInfo.defaultOpt.CollectionFormat = sv.gbind.options.collectionFormat
+	}
+	fInfo.defaultOpt.setter, fInfo.defaultOpt.setterType = newSetter(rt), rt
+
 	sv.fields[ns] = fInfo
+	sv.fieldList = append(sv.fieldList, fInfo)
+
+	// sanitize tag
+	if v, ok := field.Tag.Lookup(sv.gbind.options.sanitizeTagName); ok {
+		sanitizers, err := sv.parseSanitizeTag(v, ns)
+		if err != nil {
+			return err
+		}
+		if len(sanitizers) > 0 {
+			fInfo.sanitizers = sanitizers
+			sv.sanitizeFields = append(sv.sanitizeFields, fInfo)
+		}
+	}
 
 	bindTag, ok := field.Tag.Lookup(sv.gbind.options.bindTagName)
 	if !ok {
 		return nil
 	}
 
-	// default tag
-	bindTagValue := defaultOpt(bindTag, &fInfo.defaultOpt)
+	// options behind the source
+	bindTagValue, tagOpts := tags.Split(bindTag)
+	for _, o := range tagOpts {
+		switch o.Key {
+		case "default":
+			fInfo.defaultOpt.IsDefaultExists = true
+			fInfo.defaultOpt.DefaultValue = o.Value
+		case "default_func":
+			sv.depend(registryDefaultFuncs, o.Value)
+			fn, ok := sv.gbind.defaultFuncs.get(o.Value)
+			if !ok {
+				return e("unknown default_func %q of %s", o.Value, ns)
+			}
+			fInfo.defaultOpt.defaultFunc = fn
+			fInfo.defaultOpt.field = field
+			sv.httpBinder = false
+		case "max_items", "max_len":
+			n, err := strconv.Atoi(o.Value)
+			if err != nil || n < 0 {
+				return e("invalid %s %q of %s", o.Key, o.Value, ns)
+			}
+			if o.Key == "max_items" {
+				fInfo.defaultOpt.maxItems = n
+			} else {
+				fInfo.defaultOpt.maxLen = n
+			}
+		case "collection":
+			f := CollectionFormat(o.Value)
+			if !isCollectionFormat(f) {
+				return e("unknown collection format %q of %s", o.Value, ns)
+			}
+			if !collected {
+				return e("collection format %q of %s which is bound as a single value", o.Value, ns)
+			}
+			fInfo.defaultOpt.CollectionFormat = f
+		case "split":
+			if o.Value == "" {
+				return e("empty split of %s", ns)
+			}
+			fInfo.defaultOpt.transforms = append(fInfo.defaultOpt.transforms, transformer{split: o.Value})
+		default:
+			sv.depend(registryTransforms, o.Key)
+			fn, ok := sv.gbind.transforms.get(o.Key)
+			if !ok && sv.gbind.options.disallowUnknownOptions {
+				return e("unknown transform %q of %s", o.Key, ns)
+			}
+			if !ok {
+				// ignored like the previous releases, e.g. b of `default=a,b`
+				continue
+			}
+			fInfo.defaultOpt.transforms = append(fInfo.defaultOpt.transforms, transformer{name: o.Key, fn: fn})
+			if !slices.Contains(tags.Transforms, o.Key) || sv.gbind.transformsReplaced.Load() {
+				sv.httpBinder = false
+			}
+		}
+	}
+
+	if fInfo.defaultOpt.IsDefaultExists {
+		fInfo.defaultOpt.defaults = strings.Split(fInfo.defaultOpt.DefaultValue, fInfo.defaultOpt.DefaultSplitFlag)
+	}
 
 	// excer
+	source, _ := head(bindTagValue, ".")
+	sv.depend(registryExecers, source)
 	excer, err := sv.gbind.tagExcers.getExecer(StringToSlice(bindTagValue))
 	if err != nil {
 		return nil
 	}
 	fInfo.excer = excer
+	if fInfo.concurrent = isConcurrent(excer); fInfo.concurrent {
+		sv.concurrentFields = append(sv.concurrentFields, len(sv.execFields))
+	}
+	sv.execFields = append(sv.execFields, fInfo)
+
+	// the known params for WithDisallowUnknownParams
+	switch ex := excer.(type) {
+	case *httpQueryExcer:
+		sv.queryParams.add(ex.param, fInfo.defaultOpt.CollectionFormat)
+	case *httpFormExcer:
+		sv.formParams.add(ex.param, fInfo.defaultOpt.CollectionFormat)
+	case *httpPathExcer, *httpHeadExcer, *httpCookieExcer:
+	default:
+		sv.httpBinder = false
+	}
 	return nil
 }
 
+// errTags saves the err_msg and its localized variants of the field
+func (sv *structType) errTags(field reflect.StructField, ns string) {
+	if v, ok := field.Tag.Lookup(sv.gbind.options.errTagName); ok {
+		sv.errMap[ns] = v
+	}
+	if m := localeTags(field.Tag, sv.gbind.options.errTagName); m != nil {
+		sv.localeErrMap[ns] = m
+	}
+}
+
+// traverseElemErrTags saves the err_msg of the struct elements in a slice, array or map,
+// the elements are validated by `dive` with the namespace like Req.Items[2].Qty,
+// which is saved as Req.Items.Qty
+func (sv *structType) traverseElemErrTags(rt reflect.Type, ns string, visiting map[reflect.Type]bool) {
+	rt = deref(rt)
+	if isContainer(rt) {
+		sv.traverseElemErrTags(rt.Elem(), ns, visiting)
+		return
+	}
+	if rt.Kind() != reflect.Struct || visiting[rt] {
+		return
+	}
+	visiting[rt] = true
+	defer delete(visiting, rt)
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.Anonymous && field.PkgPath != "" {
+			continue
+		}
+		fns := namespace(field, ns)
+		sv.errTags(field, fns)
+		sv.traverseElemErrTags(field.Type, fns, visiting)
+	}
+}
+
 func (sv *structType) parseJSON(data interface{}, obj interface{}) error {
 	req, ok := data.(*http.Request)
 	if !ok || req == nil || req.Body == nil {
 		return e("invalid request")
 	}
-	decoder := json.NewDecoder(req.Body)
-	if sv.gbind.options.useNumberForJSON {
-		decoder.UseNumber()
+	var body io.Reader = req.Body
+	if maxDepth := sv.gbind.options.limits.maxDepth; maxDepth > 0 {
+		body = &depthReader{r: body, max: maxDepth}
+	}
+	var err error
+	if sv.gbind.options.disallowUnknownFields || sv.gbind.options.disallowTrailingData {
+		err = sv.strictDecode(body, obj)
+	} else {
+		decoder := json.NewDecoder(body)
+		if sv.gbind.options.useNumberForJSON {
+			decoder.UseNumber()
+		}
+		err = decoder.Decode(obj)
 	}
-	if err := decoder.Decode(obj); err != nil {
+	if err != nil {
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return limitErr
+		}
 		return err
 	}
 	return nil
@@ -342,19 +915,6 @@ func head(str, sep string) (head string, tail string) {
 	return str[:idx], str[idx+len(sep):]
 }
 
-func defaultOpt(tag string, opt *DefaultOption) string {
-	excerTag, tail := head(tag, ",")
-	var left string
-	for len(tail) > 0 {
-		left, tail = head(tail, ",")
-		if k, v := head(left, "="); k == "default" {
-			opt.IsDefaultExists = true
-			opt.DefaultValue = v
-		}
-	}
-	return excerTag
-}
-
 func namespace(field reflect.StructField, ns string) string {
 	if field.Name != "" {
 		ns = ns + "." + field.Name
@@ -362,6 +922,36 @@ func namespace(field reflect.StructField, ns string) string {
 	return ns
 }
 
+func isContainer(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// trimIndexes removes the indexes from the namespace, Req.Items[2].Qty => Req.Items.Qty
+func trimIndexes(ns string) string {
+	if !strings.Contains(ns, "[") {
+		return ns
+	}
+	var (
+		b     strings.Builder
+		depth int
+	)
+	for _, c := range ns {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
 func deref(t reflect.Type) reflect.Type {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -3,9 +3,12 @@ package gbind
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 )
@@ -278,6 +281,46 @@ func TestRegisterBindFunc(t *testing.T) {
 	}
 }
 
+func TestRegisterDefaultFunc(t *testing.T) {
+	type requestIDKey struct{}
+	g := NewGbind()
+	g.RegisterDefaultFunc("request_id", func(ctx context.Context, field reflect.StructField) (string, error) {
+		return ctx.Value(requestIDKey{}).(string), nil
+	})
+	g.RegisterDefaultFunc("field_name", func(ctx context.Context, field reflect.StructField) (string, error) {
+		return field.Name, nil
+	})
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+
+	{
+		type Foo struct {
+			RequestID string `gbind:"http.header.X-Request-Id,default_func=request_id"`
+			Name      string `gbind:"http.query.name,default=abc,default_func=field_name"`
+		}
+		f := &Foo{}
+		req := newReq().r()
+		_, err := g.Bind(ctx, f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "req-1", f.RequestID)
+		assert.Equal(t, "Name", f.Name)
+
+		f = &Foo{}
+		req = newReq().addHeader("X-Request-Id", "req-2").r()
+		_, err = g.Bind(ctx, f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "req-2", f.RequestID)
+	}
+
+	// unknown default_func
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name,default_func=unknown"`
+		}
+		_, err := g.Bind(ctx, &Foo{}, newReq().r())
+		assert.NotNil(t, err)
+	}
+}
+
 func TestRegisterCustomValidation(t *testing.T) {
 	g := NewGbind()
 
@@ -296,6 +339,153 @@ func TestRegisterCustomValidation(t *testing.T) {
 	}
 }
 
+type ctxValidator struct {
+	key interface{}
+}
+
+func (v *ctxValidator) ValidateStruct(obj interface{}) error {
+	return v.ValidateStructCtx(context.Background(), obj)
+}
+
+func (v *ctxValidator) ValidateStructCtx(ctx context.Context, obj interface{}) error {
+	if ctx.Value(v.key) == nil {
+		return errors.New("missing ctx value")
+	}
+	return nil
+}
+
+type nopValidator struct{}
+
+func (nopValidator) ValidateStruct(interface{}) error { return nil }
+
+func TestWithValidator(t *testing.T) {
+	type Foo struct {
+		Appkey string `gbind:"http.query.appkey" validate:"required"`
+	}
+
+	// no-op
+	{
+		g := NewGbind(WithValidator(nopValidator{}))
+		_, err := g.BindWithValidate(context.Background(), &Foo{}, newReq().r())
+		assert.Nil(t, err)
+
+		err = g.RegisterCustomValidation("is-awesome", func(fl validator.FieldLevel) bool { return true })
+		assert.NotNil(t, err)
+	}
+
+	// context
+	{
+		type key struct{}
+		g := NewGbind(WithValidator(&ctxValidator{key: key{}}))
+		_, err := g.BindWithValidate(context.Background(), &Foo{}, newReq().r())
+		assert.NotNil(t, err)
+
+		_, err = g.BindWithValidate(context.WithValue(context.Background(), key{}, 1), &Foo{}, newReq().r())
+		assert.Nil(t, err)
+	}
+}
+
+func TestRegisterCustomValidationCtx(t *testing.T) {
+	type tenantKey struct{}
+	g := NewGbind()
+	g.RegisterCustomValidationCtx("tenant", func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fl.Field().String() == ctx.Value(tenantKey{})
+	})
+
+	type Foo struct {
+		Tenant string `gbind:"http.query.tenant" validate:"tenant"`
+	}
+	ctx := context.WithValue(context.Background(), tenantKey{}, "t1")
+	_, err := g.BindWithValidate(ctx, &Foo{}, newReq().addQueryParam("tenant", "t1").r())
+	assert.Nil(t, err)
+
+	_, err = g.BindWithValidate(ctx, &Foo{}, newReq().addQueryParam("tenant", "t2").r())
+	assert.NotNil(t, err)
+}
+
+func TestValidateSetup(t *testing.T) {
+	// validate tag
+	{
+		type Foo struct {
+			Appkey string `gbind:"http.query.appkey" check:"required"`
+		}
+		g := NewGbind(WithValidateTag("check"))
+		_, err := g.BindWithValidate(context.Background(), &Foo{}, newReq().r())
+		assert.NotNil(t, err)
+
+		_, err = g.BindWithValidate(context.Background(), &Foo{}, newReq().addQueryParam("appkey", "a").r())
+		assert.Nil(t, err)
+	}
+
+	// alias and err_msg with the tag name func
+	{
+		type Bar struct {
+			Phone string `gbind:"http.query.phone" label:"mobile" validate:"phone" err_msg:"invalid phone"`
+		}
+		g := NewGbind(
+			WithValidationAlias("phone", "numeric,len=11"),
+			WithValidationTagNameFunc(func(field reflect.StructField) string {
+				return field.Tag.Get("label")
+			}),
+		)
+		_, err := g.BindWithValidate(context.Background(), &Bar{}, newReq().addQueryParam("phone", "123").r())
+		assert.NotNil(t, err)
+		assert.Equal(t, "invalid phone", err.Error())
+
+		g = NewGbind(WithErrTag("-"), WithValidationAlias("phone", "numeric,len=11"), WithValidationTagNameFunc(func(field reflect.StructField) string {
+			return field.Tag.Get("label")
+		}))
+		_, err = g.BindWithValidate(context.Background(), &Bar{}, newReq().addQueryParam("phone", "123").r())
+		errs, ok := err.(validator.ValidationErrors)
+		assert.True(t, ok)
+		assert.Equal(t, "Bar.mobile", errs[0].Namespace())
+
+		_, err = g.BindWithValidate(context.Background(), &Bar{}, newReq().addQueryParam("phone", "13800000000").r())
+		assert.Nil(t, err)
+	}
+
+	// struct validation
+	{
+		type Bar struct {
+			Min int `gbind:"http.query.min"`
+			Max int `gbind:"http.query.max"`
+		}
+		g := NewGbind(WithStructValidation(func(sl validator.StructLevel) {
+			bar := sl.Current().Interface().(Bar)
+			if bar.Min > bar.Max {
+				sl.ReportError(bar.Min, "Min", "Min", "ltefield", "Max")
+			}
+		}, Bar{}))
+		_, err := g.BindWithValidate(context.Background(), &Bar{}, newReq().addQueryParam("min", "2").addQueryParam("max", "1").r())
+		assert.NotNil(t, err)
+	}
+
+	// translation
+	{
+		type Bar struct {
+			Appkey string `gbind:"http.query.appkey" validate:"required"`
+		}
+		trans, _ := ut.New(en.New()).GetTranslator("en")
+		g := NewGbind(WithValidationTranslation("required", trans, func(trans ut.Translator) error {
+			return trans.Add("required", "{0} is a must", true)
+		}, func(trans ut.Translator, fe validator.FieldError) string {
+			msg, _ := trans.T("required", fe.Field())
+			return msg
+		}))
+		_, err := g.BindWithValidate(context.Background(), &Bar{}, newReq().r())
+		errs, ok := err.(validator.ValidationErrors)
+		assert.True(t, ok)
+		assert.Equal(t, "Appkey is a must", errs[0].Translate(trans))
+	}
+
+	// setup error
+	{
+		g := NewGbind(WithValidation("", func(fl validator.FieldLevel) bool { return true }))
+		_, err := g.BindWithValidate(context.Background(), &struct{}{}, newReq().r())
+		assert.NotNil(t, err)
+	}
+}
+
 func TestJosn(t *testing.T) {
 	{
 		type Foo struct {
@@ -331,6 +521,66 @@ func TestJosn(t *testing.T) {
 	}
 }
 
+func TestDive(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" validate:"required" err_msg:"{field} of the item is required"`
+		Qty  int    `json:"qty" validate:"gte=1,lte=10" err_msg:"gte=at least one;lte=at most {param}, got {value}"`
+	}
+
+	// nested slice and map
+	{
+		type Req struct {
+			Items []*Item         `json:"items" validate:"required,dive"`
+			Attrs map[string]Item `json:"attrs" validate:"dive"`
+			Tags  []string        `json:"tags" validate:"dive,max=3" err_msg:"tag is too long"`
+		}
+		req := newReq().setBody(`{"items":[{"name":"a","qty":1},{"name":"b","qty":11}]}`).r()
+		_, err := BindWithValidate(context.Background(), &Req{}, req)
+		assert.NotNil(t, err)
+		assert.Equal(t, "at most 10, got 11", err.Error())
+
+		req = newReq().setBody(`{"items":[{"name":"a","qty":1}],"attrs":{"x":{"qty":1}}}`).r()
+		_, err = BindWithValidate(context.Background(), &Req{}, req)
+		assert.NotNil(t, err)
+		assert.Equal(t, "Name of the item is required", err.Error())
+
+		req = newReq().setBody(`{"items":[{"name":"a","qty":1}],"tags":["a","abcd"]}`).r()
+		_, err = BindWithValidate(context.Background(), &Req{}, req)
+		assert.NotNil(t, err)
+		assert.Equal(t, "tag is too long", err.Error())
+	}
+
+	// top-level slice
+	{
+		items := []Item{}
+		req := newReq().setBody(`[{"name":"a","qty":1},{"name":"b","qty":0},{"qty":1}]`).r()
+		_, err := BindWithValidate(context.Background(), &items, req)
+		assert.NotNil(t, err)
+		errs, ok := err.(ElemValidateErrors)
+		assert.True(t, ok)
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, "1", errs[0].Index)
+		assert.Equal(t, "at least one", errs[0].Err.Error())
+		assert.Equal(t, "2", errs[1].Index)
+		assert.Equal(t, "[1]: at least one\n[2]: Name of the item is required", err.Error())
+
+		items = []Item{}
+		req = newReq().setBody(`[{"name":"a","qty":1}]`).r()
+		_, err = BindWithValidate(context.Background(), &items, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{Name: "a", Qty: 1}}, items)
+	}
+
+	// top-level map
+	{
+		items := map[string]*Item{}
+		req := newReq().setBody(`{"b":{"name":"b","qty":1},"a":{"qty":1}}`).r()
+		_, err := BindWithValidate(context.Background(), &items, req)
+		assert.NotNil(t, err)
+		assert.Equal(t, "[a]: Name of the item is required", err.Error())
+	}
+}
+
 func TestCheckValid(t *testing.T) {
 	type Foo struct{}
 	{
@@ -353,4 +603,14 @@ func TestCheckValid(t *testing.T) {
 		err := NewGbind().checkValid(reflect.ValueOf(&f))
 		assert.NotNil(t, err)
 	}
+	{
+		var f = []*Foo{}
+		err := NewGbind().checkValid(reflect.ValueOf(&f))
+		assert.Nil(t, err)
+	}
+	{
+		var f = []int{}
+		err := NewGbind().checkValid(reflect.ValueOf(&f))
+		assert.NotNil(t, err)
+	}
 }
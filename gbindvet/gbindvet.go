@@ -0,0 +1,340 @@
+// Package gbindvet defines an Analyzer that checks the gbind, err_msg and validate tags.
+//
+// The mistakes in the tags are only found when a struct is bound at runtime,
+// or never since a field with an unknown source is not bound at all,
+// the analyzer reports them by go vet:
+//
+//	go install github.com/bdjimmy/gbind/cmd/gbindvet@latest
+//	go vet -vettool=$(which gbindvet) ./...
+package gbindvet
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bdjimmy/gbind"
+	"github.com/bdjimmy/gbind/internal/gotypes"
+	"github.com/bdjimmy/gbind/internal/tags"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `check the gbind, err_msg and validate struct tags
+
+The analyzer reports the unknown sources and the wrong arity of the gbind tag,
+the defaults which can not be parsed into the field type, the invalid and unknown
+options, the unknown validate rules and the err_msg rules which are not in the validate tag.
+
+The sources registered by RegisterBindFunc, the rules registered by RegisterCustomValidation,
+the transforms registered by RegisterTransform and the functions registered by
+RegisterDefaultFunc are declared by the -sources, -validations, -transforms and
+-defaultfuncs flags.`
+
+// Analyzer checks the gbind, err_msg and validate tags
+var Analyzer = &analysis.Analyzer{
+	Name:     "gbind",
+	Doc:      doc,
+	Flags:    flags(),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	bindTag           string
+	errTag            string
+	validateTag       string
+	splitFlag         string
+	extraSources      string
+	extraRules        string
+	extraTransforms   string
+	extraDefaultFuncs string
+)
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("gbind", flag.ExitOnError)
+	fs.StringVar(&bindTag, "tag", "gbind", "the bind tag name, see WithBindTag")
+	fs.StringVar(&errTag, "errtag", "err_msg", "the err_msg tag name, see WithErrTag")
+	fs.StringVar(&validateTag, "validatetag", "validate", "the validate tag name, see WithValidateTag")
+	fs.StringVar(&splitFlag, "split", "|", "the split flag of the default values, see WithDefaultSplitFlag")
+	fs.StringVar(&extraSources, "sources", "", "comma-separated sources registered by RegisterBindFunc")
+	fs.StringVar(&extraRules, "validations", "", "comma-separated rules registered by RegisterCustomValidation")
+	fs.StringVar(&extraTransforms, "transforms", "", "comma-separated transforms registered by RegisterTransform")
+	fs.StringVar(&extraDefaultFuncs, "defaultfuncs", "", "comma-separated functions registered by RegisterDefaultFunc")
+	return *fs
+}
+
+// requestRules the validations registered by gbind.NewGbind
+var requestRules = []string{
+	"required_if_method",
+	"required_with_query",
+	"required_without_query",
+	"required_with_header",
+	"required_without_header",
+}
+
+var collectionFormats = map[gbind.CollectionFormat]bool{
+	gbind.CollectionMulti:    true,
+	gbind.CollectionCSV:      true,
+	gbind.CollectionSSV:      true,
+	gbind.CollectionPipes:    true,
+	gbind.CollectionBrackets: true,
+	gbind.CollectionIndexed:  true,
+}
+
+type checker struct {
+	pass         *analysis.Pass
+	sources      map[string]bool
+	transforms   map[string]bool
+	defaultFuncs map[string]bool
+	validate     *validator.Validate
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{
+		pass:         pass,
+		sources:      map[string]bool{"http": true},
+		transforms:   map[string]bool{},
+		defaultFuncs: map[string]bool{},
+		validate:     validator.New(),
+	}
+	for _, s := range split(extraSources) {
+		c.sources[s] = true
+	}
+	for _, t := range append(split(extraTransforms), tags.Transforms...) {
+		c.transforms[t] = true
+	}
+	for _, fn := range split(extraDefaultFuncs) {
+		c.defaultFuncs[fn] = true
+	}
+	for _, rule := range append(split(extraRules), requestRules...) {
+		if err := c.validate.RegisterValidation(rule, func(validator.FieldLevel) bool { return true }); err != nil {
+			return nil, fmt.Errorf("gbind: invalid validation %q: %v", rule, err)
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		c.checkStruct(n.(*ast.StructType))
+	})
+	return nil, nil
+}
+
+// checkStruct checks the tags of every field of the struct
+func (c *checker) checkStruct(st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		fieldTag := reflect.StructTag(tag)
+		typ := c.pass.TypesInfo.TypeOf(field.Type)
+		name := fieldName(field)
+		if v, ok := fieldTag.Lookup(bindTag); ok {
+			c.checkBindTag(field, name, typ, v)
+		}
+		// the err_msg of the fields without the bind tag is used too, e.g. the fields of
+		// the nested struct bound by the json tag of the parent, which is not known here
+		rules := c.checkValidateTag(field, name, fieldTag)
+		for _, pair := range tags.Pairs(fieldTag) {
+			if pair.Key == errTag || strings.HasPrefix(pair.Key, errTag+"_") {
+				c.checkErrTag(field, name, pair.Key, pair.Value, rules)
+			}
+		}
+	}
+}
+
+// checkBindTag checks the source and the options of `gbind:"http.query.ids,default=1|2"`
+func (c *checker) checkBindTag(field *ast.Field, name string, typ types.Type, tag string) {
+	source, opts := tags.Split(tag)
+	parts := strings.Split(source, ".")
+	switch {
+	case !c.sources[parts[0]]:
+		c.pass.Reportf(field.Tag.Pos(), "unknown %s source %q of %s", bindTag, source, name)
+	case parts[0] == "http":
+		if len(parts) < 2 {
+			c.pass.Reportf(field.Tag.Pos(), "%s source %q of %s needs one of http.path, http.query, http.form, http.header, http.cookie", bindTag, source, name)
+			break
+		}
+		arity, ok := tags.HTTPArity[parts[1]]
+		switch {
+		case !ok:
+			c.pass.Reportf(field.Tag.Pos(), "unknown %s source %q of %s", bindTag, source, name)
+		case arity == 2 && len(parts) != 2:
+			c.pass.Reportf(field.Tag.Pos(), "%s source %q of %s takes no name, use http.%s", bindTag, source, name, parts[1])
+		case arity == 3 && (len(parts) != 3 || parts[2] == ""):
+			c.pass.Reportf(field.Tag.Pos(), "%s source %q of %s needs a name, use http.%s.name", bindTag, source, name, parts[1])
+		}
+	}
+
+	for _, o := range opts {
+		switch o.Key {
+		case "default":
+			if err := parseDefault(typ, o.Value); err != nil {
+				c.pass.Reportf(field.Tag.Pos(), "invalid default %q of %s: %v", o.Value, name, err)
+			}
+		case "default_func":
+			if !c.defaultFuncs[o.Value] {
+				c.pass.Reportf(field.Tag.Pos(), "unknown default_func %q of %s", o.Value, name)
+			}
+		case "max_items", "max_len":
+			if n, err := strconv.Atoi(o.Value); err != nil || n < 0 {
+				c.pass.Reportf(field.Tag.Pos(), "invalid %s %q of %s", o.Key, o.Value, name)
+			}
+		case "collection":
+			if !collectionFormats[gbind.CollectionFormat(o.Value)] {
+				c.pass.Reportf(field.Tag.Pos(), "unknown collection format %q of %s", o.Value, name)
+			}
+			if !gotypes.IsCollected(gotypes.Deref(typ)) {
+				c.pass.Reportf(field.Tag.Pos(), "collection format %q of %s which is bound as a single value", o.Value, name)
+			}
+		case "split":
+			if o.Value == "" {
+				c.pass.Reportf(field.Tag.Pos(), "empty split of %s", name)
+			}
+		default:
+			if !c.transforms[o.Key] {
+				c.pass.Reportf(field.Tag.Pos(), "unknown %s option %q of %s", bindTag, o.Key, name)
+			}
+		}
+	}
+}
+
+// checkValidateTag reports the unknown rules of the validate tag, and returns the rules
+func (c *checker) checkValidateTag(field *ast.Field, name string, tag reflect.StructTag) map[string]bool {
+	v, ok := tag.Lookup(validateTag)
+	if !ok {
+		return nil
+	}
+	if msg := c.parseValidateTag(v); msg != "" {
+		c.pass.Reportf(field.Tag.Pos(), "invalid %s tag of %s: %s", validateTag, name, msg)
+	}
+	rules := map[string]bool{}
+	for _, rule := range strings.Split(v, ",") {
+		for _, r := range strings.Split(rule, "|") {
+			r, _ = head(r, "=")
+			rules[r] = true
+		}
+	}
+	return rules
+}
+
+// parseValidateTag the validator panics when a tag is invalid,
+// nil is validated so that only the parsing of the tag may panic
+func (c *checker) parseValidateTag(tag string) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = strings.TrimSuffix(fmt.Sprint(r), " on field ''")
+		}
+	}()
+	_ = c.validate.Var(nil, tag)
+	return ""
+}
+
+// checkErrTag reports the rules of `err_msg:"required=please login;max=too long"` which are not validated
+func (c *checker) checkErrTag(field *ast.Field, name, key, msg string, rules map[string]bool) {
+	if rules == nil || !strings.Contains(msg, "=") {
+		return
+	}
+	parts := strings.Split(msg, ";")
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		rule, _ := head(part, "=")
+		rule = strings.TrimSpace(rule)
+		if !tags.IsRuleName(rule) || len(rule) == len(part) {
+			return
+		}
+		names = append(names, rule)
+	}
+	for _, rule := range names {
+		if !rules[rule] {
+			c.pass.Reportf(field.Tag.Pos(), "%s rule %q of %s is not in the %s tag", key, rule, name, validateTag)
+		}
+	}
+}
+
+// parseDefault parses the default value into the type like gbind.TrySet
+func parseDefault(typ types.Type, v string) error {
+	typ = gotypes.Deref(typ)
+	if gotypes.IsDuration(typ) {
+		_, err := time.ParseDuration(v)
+		return err
+	}
+	vs := strings.Split(v, splitFlag)
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		return parseElems(t.Elem(), vs)
+	case *types.Array:
+		if int64(len(vs)) != t.Len() {
+			return fmt.Errorf("%d values for %s", len(vs), typ)
+		}
+		return parseElems(t.Elem(), vs)
+	}
+	return parseBasic(typ, vs[0])
+}
+
+func parseElems(elem types.Type, vs []string) error {
+	for _, v := range vs {
+		if err := parseBasic(gotypes.Deref(elem), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseBasic(typ types.Type, v string) error {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok || v == "" {
+		return nil
+	}
+	var err error
+	switch info := basic.Info(); {
+	case info&types.IsBoolean != 0:
+		_, err = strconv.ParseBool(v)
+	case info&types.IsUnsigned != 0:
+		_, err = strconv.ParseUint(v, 10, gotypes.BitSize(basic.Kind()))
+	case info&types.IsInteger != 0:
+		_, err = strconv.ParseInt(v, 10, gotypes.BitSize(basic.Kind()))
+	case info&types.IsFloat != 0:
+		_, err = strconv.ParseFloat(v, gotypes.BitSize(basic.Kind()))
+	}
+	if err != nil {
+		return fmt.Errorf("not a valid %s", typ)
+	}
+	return nil
+}
+
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	return types.ExprString(field.Type)
+}
+
+func head(str, sep string) (string, string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
+
+func split(s string) []string {
+	var ss []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ss = append(ss, v)
+		}
+	}
+	return ss
+}
@@ -0,0 +1,23 @@
+package gbindvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("sources", "simple"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Analyzer.Flags.Set("validations", "is-awesome"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Analyzer.Flags.Set("transforms", "reverse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Analyzer.Flags.Set("defaultfuncs", "now"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
@@ -0,0 +1,50 @@
+package a
+
+import "time"
+
+type Params struct {
+	API     string        `gbind:"http.path"`
+	Appkey  string        `gbind:"http.query.appkey,default=abc" validate:"required" err_msg:"required=appkey is required"`
+	Page    int           `gbind:"http.query.page,default=1,max_len=3"`
+	Uids    []int         `gbind:"http.form.uids,default=1|2,collection=csv,split=,,trim"`
+	Timeout time.Duration `gbind:"http.header.timeout,default=1s"`
+	Token   *string       `gbind:"http.cookie.Token" validate:"required_if_method=POST"`
+	Key     string        `gbind:"simple.key"`
+	Checked string        `gbind:"http.query.checked" validate:"is-awesome"`
+	Name    string        `gbind:"http.query.name,trim,reverse,default_func=now"`
+	Ids     []int         `gbind:"http.query.ids,split=,"`
+}
+
+type Mistakes struct {
+	Path    string        `gbind:"http.path.api"`                                          // want `gbind source "http.path.api" of Path takes no name, use http.path`
+	Query   string        `gbind:"http.query"`                                             // want `gbind source "http.query" of Query needs a name, use http.query.name`
+	Body    string        `gbind:"http.body.name"`                                         // want `unknown gbind source "http.body.name" of Body`
+	DB      string        `gbind:"db.name"`                                                // want `unknown gbind source "db.name" of DB`
+	Page    int           `gbind:"http.query.page,default=one"`                            // want `invalid default "one" of Page: not a valid int`
+	Small   *int8         `gbind:"http.query.small,default=300"`                           // want `invalid default "300" of Small: not a valid int8`
+	Uids    []uint        `gbind:"http.query.uids,default=1|-2"`                           // want `invalid default "1|-2" of Uids: not a valid uint`
+	Pair    [2]bool       `gbind:"http.query.pair,default=true"`                           // want `invalid default "true" of Pair: 1 values for \[2\]bool`
+	Timeout time.Duration `gbind:"http.query.timeout,default=1"`                           // want `invalid default "1" of Timeout`
+	Limit   []int         `gbind:"http.query.limit,max_items=-1,collection=tsv"`           // want `invalid max_items "-1" of Limit` `unknown collection format "tsv" of Limit`
+	Single  string        `gbind:"http.query.single,collection=csv"`                       // want `collection format "csv" of Single which is bound as a single value`
+	Rule    string        `gbind:"http.query.rule" validate:"requird"`                     // want `invalid validate tag of Rule: Undefined validation function 'requird'`
+	Msg     string        `gbind:"http.query.msg" validate:"required" err_msg_zh:"max=太长"` // want `err_msg_zh rule "max" of Msg is not in the validate tag`
+	Typo    int           `gbind:"http.query.typo,defualt=1"`                              // want `unknown gbind option "defualt" of Typo`
+	Trim    string        `gbind:"http.query.trim,tirm"`                                   // want `unknown gbind option "tirm" of Trim`
+	Func    string        `gbind:"http.query.func,default_func=today"`                     // want `unknown default_func "today" of Func`
+	Split   []int         `gbind:"http.query.split,split=,trim"`                           // want `empty split of Split`
+}
+
+type Body struct {
+	Name  string `json:"name"`
+	Alias string `validate:"required" err_msg:"alias is required"`
+}
+
+// Addr the fields are bound by the json tag of the parent
+type Addr struct {
+	City string `validate:"required" err_msg:"city is required"`
+}
+
+type User struct {
+	Addr Addr `json:"addr"`
+}
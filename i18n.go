@@ -0,0 +1,150 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bdjimmy/gbind/internal/tags"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	zh_tw_translations "github.com/go-playground/validator/v10/translations/zh_tw"
+)
+
+// defaultTranslations the built-in translations of the validator, keyed by the lower case locale
+var defaultTranslations = map[string]func(v *validator.Validate, trans ut.Translator) error{
+	"en":         en_translations.RegisterDefaultTranslations,
+	"zh":         zh_translations.RegisterDefaultTranslations,
+	"zh_hant_tw": zh_tw_translations.RegisterDefaultTranslations,
+}
+
+// registerDefaultTranslations registers the built-in translations for the locales supported by uni
+func registerDefaultTranslations(uni *ut.UniversalTranslator) ValidateSetup {
+	return func(v *validator.Validate) error {
+		// the fallback is not one of the supported locales of uni unless it is passed twice
+		fallback := uni.GetFallback()
+		if register, ok := defaultTranslations[strings.ToLower(fallback.Locale())]; ok {
+			if err := register(v, fallback); err != nil {
+				return err
+			}
+		}
+		for locale, register := range defaultTranslations {
+			trans, found := uni.GetTranslator(locale)
+			if !found || trans == fallback {
+				continue
+			}
+			if err := register(v, trans); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of ctx carrying the locale of the validation messages,
+// it takes precedence over the Accept-Language header of the request
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// bindLocales returns the preferred locales of the binding in order,
+// the locale of ctx if exists, otherwise the Accept-Language header of the request
+func bindLocales(ctx context.Context, data interface{}) []string {
+	var locales []string
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return appendLocale(locales, locale)
+	}
+	if req, ok := data.(*http.Request); ok && req != nil {
+		for _, locale := range acceptLanguages(req.Header.Get("Accept-Language")) {
+			locales = appendLocale(locales, locale)
+		}
+	}
+	return locales
+}
+
+// appendLocale appends the normalized locale and its base language, zh-CN => zh_cn, zh
+func appendLocale(locales []string, locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "-", "_"))
+	if locale == "" || locale == "*" {
+		return locales
+	}
+	locales = append(locales, locale)
+	if base, _ := head(locale, "_"); base != locale {
+		locales = append(locales, base)
+	}
+	return locales
+}
+
+// acceptLanguages parses the Accept-Language header, sorted by the quality value
+func acceptLanguages(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		tag, params := head(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if k, v := head(strings.TrimSpace(params), "="); k == "q" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, lang{tag: tag, q: q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	tags := make([]string, 0, len(langs))
+	for _, l := range langs {
+		tags = append(tags, l.tag)
+	}
+	return tags
+}
+
+// localeTags returns the localized variants of the tag, `err_msg_zh:"..."` => {"zh": "..."}
+func localeTags(tag reflect.StructTag, name string) map[string]string {
+	var (
+		prefix = name + "_"
+		m      map[string]string
+	)
+	for _, pair := range tags.Pairs(tag) {
+		if !strings.HasPrefix(pair.Key, prefix) || len(pair.Key) == len(prefix) {
+			continue
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[strings.ToLower(strings.ReplaceAll(pair.Key[len(prefix):], "-", "_"))] = pair.Value
+	}
+	return m
+}
+
+// TranslatedError a validation error translated to the locale of the binding,
+// Unwrap returns the validator.ValidationErrors
+type TranslatedError struct {
+	Message string
+	Locale  string
+	errs    validator.ValidationErrors
+}
+
+// Error returns the translated message
+func (e *TranslatedError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the validator.ValidationErrors
+func (e *TranslatedError) Unwrap() error {
+	return e.errs
+}
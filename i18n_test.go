@@ -0,0 +1,76 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptLanguages(t *testing.T) {
+	for header, expect := range map[string][]string{
+		"":                                    {},
+		"zh-CN":                               {"zh-CN"},
+		"en;q=0.8, zh-CN,zh;q=0.9":            {"zh-CN", "zh", "en"},
+		"fr;q=0, en;q=0.5, ja;q=abc, *;q=0.1": {"ja", "en", "*"},
+	} {
+		assert.Equal(t, expect, acceptLanguages(header), header)
+	}
+}
+
+func TestLocaleTags(t *testing.T) {
+	type Foo struct {
+		A string `err_msg:"a" err_msg_zh:"中文" err_msg_zh-TW:"繁體" err_msg_:"x" validate:"required"`
+		B string `json:"b"`
+	}
+	rt := reflect.TypeOf(Foo{})
+	assert.Equal(t, map[string]string{"zh": "中文", "zh_tw": "繁體"}, localeTags(rt.Field(0).Tag, "err_msg"))
+	assert.Nil(t, localeTags(rt.Field(1).Tag, "err_msg"))
+}
+
+func TestLocaleErrMsg(t *testing.T) {
+	type Foo struct {
+		Token string `gbind:"http.cookie.Token" validate:"required" err_msg:"please login" err_msg_zh:"请先登录"`
+	}
+
+	// tag variants
+	{
+		_, err := BindWithValidate(context.Background(), &Foo{}, newReq().addHeader("Accept-Language", "zh-CN,zh;q=0.9").r())
+		assert.Equal(t, "请先登录", err.Error())
+
+		_, err = BindWithValidate(context.Background(), &Foo{}, newReq().addHeader("Accept-Language", "fr").r())
+		assert.Equal(t, "please login", err.Error())
+
+		ctx := ContextWithLocale(context.Background(), "en")
+		_, err = BindWithValidate(ctx, &Foo{}, newReq().addHeader("Accept-Language", "zh").r())
+		assert.Equal(t, "please login", err.Error())
+	}
+
+	// translator
+	{
+		type Bar struct {
+			Appkey string `gbind:"http.query.appkey" validate:"required"`
+		}
+		g := NewGbind(WithTranslator(ut.New(en.New(), zh.New())))
+
+		_, err := g.BindWithValidate(context.Background(), &Bar{}, newReq().addHeader("Accept-Language", "zh-CN").r())
+		var terr *TranslatedError
+		assert.True(t, errors.As(err, &terr))
+		assert.Equal(t, "zh", terr.Locale)
+		assert.Equal(t, "Appkey为必填字段", err.Error())
+		var verrs validator.ValidationErrors
+		assert.True(t, errors.As(err, &verrs))
+
+		_, err = g.BindWithValidate(context.Background(), &Bar{}, newReq().addHeader("Accept-Language", "ja").r())
+		assert.Equal(t, "Appkey is a required field", err.Error())
+
+		_, err = g.BindWithValidate(context.Background(), &Foo{}, newReq().addHeader("Accept-Language", "zh").r())
+		assert.Equal(t, "请先登录", err.Error())
+	}
+}
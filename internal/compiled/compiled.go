@@ -0,0 +1,38 @@
+// Package compiled exposes the fields compiled by gbind to the other packages of the module
+package compiled
+
+import "reflect"
+
+// Field a field of the struct compiled by gbind
+type Field struct {
+	// Namespace the namespace of the field, e.g. Params.Uids
+	Namespace string
+	// StructField the struct field, its type is the type of the field
+	StructField reflect.StructField
+	// Source the Name() of the execer, e.g. http.query, empty if the field is not bound by the bind tag
+	Source string
+	// Param the name of the param of the http source, e.g. uids of http.query.uids
+	Param string
+	// HasDefault and Default the default= of the bind tag
+	HasDefault bool
+	Default    string
+	// DefaultSplitFlag the separator of the default values of the slices
+	DefaultSplitFlag string
+	// CollectionFormat the format of the slices in the query and form
+	CollectionFormat string
+}
+
+// Struct the struct compiled by gbind
+type Struct struct {
+	// Fields the fields in the order of the struct
+	Fields []Field
+	// JSON whether the body is decoded as json
+	JSON bool
+	// BindTag and ValidateTag the tag names of the bind tag and the validate rules
+	BindTag     string
+	ValidateTag string
+}
+
+// Compile compiles the value v by the *gbind.Gbind g, or the Gbind of the package functions if g is nil,
+// it is set by the package gbind
+var Compile func(g interface{}, v interface{}) (*Struct, error)
@@ -0,0 +1,41 @@
+package gentest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/bdjimmy/gbind"
+)
+
+func BenchmarkBindHTTP(b *testing.B) {
+	query := "appkey=%20ABC%20&pair=3&pair=4&ratio=0.5&debug=true&status=2&page=3" +
+		"&since=2022-05-01T08:00:00Z&addr=127.0.0.1&tag[]=go&id[0]=1"
+	r := newRequest(query, url.Values{"uids": {"1,2,3"}})
+	ctx := context.Background()
+
+	b.Run("generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if err := (&Params{}).BindHTTP(ctx, r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("bind-generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := gbind.Bind(ctx, &Params{}, r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("bind-reflection", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			if _, err := gbind.Bind(ctx, &reflectParams{}, r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,46 @@
+// Package gentest tests the code generated by gbindgen
+package gentest
+
+import (
+	"net"
+	"time"
+)
+
+//go:generate go run ../../cmd/gbindgen -type=Params,Empty
+
+// Status a named type of the basic type
+type Status int
+
+// Page the nested struct
+type Page struct {
+	Num  int  `gbind:"http.query.page,default=1"`
+	Size *int `gbind:"http.query.size,default=10" validate:"lte=100"`
+}
+
+// Params the struct of the generated binding
+type Params struct {
+	API     string        `gbind:"http.path"`
+	Appkey  string        `gbind:"http.query.appkey,trim,lower"`
+	Token   *string       `gbind:"http.cookie.Token"`
+	Host    string        `gbind:"http.header.host,default=www.baidu.com"`
+	Uids    []int64       `gbind:"http.form.uids,collection=csv,max_items=3"`
+	Pair    [2]uint8      `gbind:"http.query.pair,default=1|2"`
+	Ratio   float32       `gbind:"http.query.ratio"`
+	Debug   bool          `gbind:"http.query.debug"`
+	Status  Status        `gbind:"http.query.status"`
+	Timeout time.Duration `gbind:"http.header.timeout,default=1s"`
+	Since   time.Time     `gbind:"http.query.since"`
+	Until   *time.Time    `gbind:"http.query.until"`
+	Addrs   []net.IP      `gbind:"http.query.addr"`
+	Tags    []string      `gbind:"http.query.tag,collection=brackets,upper,max_len=5"`
+	Ids     []int         `gbind:"http.query.id,collection=indexed"`
+	Names   []string      `gbind:"http.header.names,split=,,trim"`
+	Page
+	Next *Page
+	skip string
+}
+
+// Empty has no fields bound by the gbind tag
+type Empty struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,374 @@
+// Code generated by "gbindgen -type=Params,Empty"; DO NOT EDIT.
+
+package gentest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bdjimmy/gbind"
+)
+
+// BindHTTP sets the fields of Params bound by the http sources of the gbind tag, it implements gbind.HTTPBinder
+func (v *Params) BindHTTP(ctx context.Context, r *http.Request) error {
+	if r == nil {
+		return gbind.ErrNilRequest
+	}
+	query := r.URL.Query()
+	form, err := gbind.PostForm(ctx, r)
+	if err != nil {
+		return err
+	}
+	var vs []string
+
+	// Params.API `gbind:"http.path"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = []string{r.URL.Path}
+	if len(vs) > 0 {
+		v.API = vs[0]
+	}
+
+	// Params.Appkey `gbind:"http.query.appkey,trim,lower"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["appkey"]
+	if len(vs) > 0 {
+		nvs := make([]string, len(vs))
+		for i, s := range vs {
+			nvs[i] = strings.ToLower(strings.TrimSpace(s))
+		}
+		vs = nvs
+	}
+	if len(vs) > 0 {
+		v.Appkey = vs[0]
+	}
+
+	// Params.Token `gbind:"http.cookie.Token"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.Token == nil {
+		v.Token = new(string)
+	}
+	vs = nil
+	if c, err := r.Cookie("Token"); err == nil {
+		s, _ := url.QueryUnescape(c.Value)
+		vs = []string{s}
+	}
+	if len(vs) > 0 {
+		*v.Token = vs[0]
+	}
+
+	// Params.Host `gbind:"http.header.host,default=www.baidu.com"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = r.Header["Host"]
+	if len(vs) == 0 {
+		vs = []string{"www.baidu.com"}
+	}
+	if len(vs) > 0 {
+		v.Host = vs[0]
+	}
+
+	// Params.Uids `gbind:"http.form.uids,collection=csv,max_items=3"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = nil
+	for _, s := range form["uids"] {
+		vs = append(vs, strings.Split(s, ",")...)
+	}
+	if len(vs) > 0 {
+		if len(vs) > 3 {
+			return &gbind.LimitError{Limit: gbind.LimitSliceLen, Max: 3, Field: "Params.Uids"}
+		}
+	}
+	if len(vs) > 0 {
+		s := make([]int64, len(vs))
+		for i, val := range vs {
+			b, err := gbind.ParseInt(val, 64)
+			if err != nil {
+				return err
+			}
+			s[i] = b
+		}
+		v.Uids = s
+	}
+
+	// Params.Pair `gbind:"http.query.pair,default=1|2"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["pair"]
+	if len(vs) == 0 {
+		vs = []string{"1", "2"}
+	}
+	if len(vs) > 0 {
+		if len(vs) != 2 {
+			return fmt.Errorf("%q is not valid value for [2]uint8", append([]string(nil), vs...))
+		}
+		for i, val := range vs {
+			b, err := gbind.ParseUint(val, 8)
+			if err != nil {
+				return err
+			}
+			v.Pair[i] = uint8(b)
+		}
+	}
+
+	// Params.Ratio `gbind:"http.query.ratio"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["ratio"]
+	if len(vs) > 0 {
+		b, err := gbind.ParseFloat(vs[0], 32)
+		if err != nil {
+			return err
+		}
+		v.Ratio = float32(b)
+	}
+
+	// Params.Debug `gbind:"http.query.debug"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["debug"]
+	if len(vs) > 0 {
+		b, err := gbind.ParseBool(vs[0])
+		if err != nil {
+			return err
+		}
+		v.Debug = b
+	}
+
+	// Params.Status `gbind:"http.query.status"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["status"]
+	if len(vs) > 0 {
+		b, err := gbind.ParseInt(vs[0], 0)
+		if err != nil {
+			return err
+		}
+		v.Status = Status(b)
+	}
+
+	// Params.Timeout `gbind:"http.header.timeout,default=1s"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = r.Header["Timeout"]
+	if len(vs) == 0 {
+		vs = []string{"1s"}
+	}
+	if len(vs) == 1 {
+		d, err := time.ParseDuration(vs[0])
+		if err != nil {
+			return err
+		}
+		v.Timeout = d
+	}
+
+	// Params.Since `gbind:"http.query.since"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["since"]
+	if len(vs) > 0 {
+		if err := v.Since.UnmarshalText([]byte(vs[0])); err != nil {
+			return err
+		}
+	}
+
+	// Params.Until `gbind:"http.query.until"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.Until == nil {
+		v.Until = new(time.Time)
+	}
+	vs = query["until"]
+	if len(vs) > 0 {
+		if err := (*v.Until).UnmarshalText([]byte(vs[0])); err != nil {
+			return err
+		}
+	}
+
+	// Params.Addrs `gbind:"http.query.addr"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["addr"]
+	if len(vs) > 0 {
+		s := make([]net.IP, len(vs))
+		for i, val := range vs {
+			if err := s[i].UnmarshalText([]byte(val)); err != nil {
+				return err
+			}
+		}
+		v.Addrs = s
+	}
+
+	// Params.Tags `gbind:"http.query.tag,collection=brackets,upper,max_len=5"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["tag[]"]
+	if len(vs) > 0 {
+		nvs := make([]string, len(vs))
+		for i, s := range vs {
+			nvs[i] = strings.ToUpper(s)
+		}
+		vs = nvs
+		for _, s := range vs {
+			if len(s) > 5 {
+				return &gbind.LimitError{Limit: gbind.LimitStringLen, Max: 5, Field: "Params.Tags"}
+			}
+		}
+	}
+	if len(vs) > 0 {
+		s := make([]string, len(vs))
+		for i, val := range vs {
+			s[i] = val
+		}
+		v.Tags = s
+	}
+
+	// Params.Ids `gbind:"http.query.id,collection=indexed"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = gbind.IndexedValues(query, "id")
+	if len(vs) > 0 {
+		s := make([]int, len(vs))
+		for i, val := range vs {
+			b, err := gbind.ParseInt(val, 0)
+			if err != nil {
+				return err
+			}
+			s[i] = int(b)
+		}
+		v.Ids = s
+	}
+
+	// Params.Names `gbind:"http.header.names,split=,,trim"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = r.Header["Names"]
+	if len(vs) > 0 {
+		nvs := make([]string, 0, len(vs))
+		for _, s := range vs {
+			nvs = append(nvs, strings.Split(s, ",")...)
+		}
+		vs = nvs
+		nvs = make([]string, len(vs))
+		for i, s := range vs {
+			nvs[i] = strings.TrimSpace(s)
+		}
+		vs = nvs
+	}
+	if len(vs) > 0 {
+		s := make([]string, len(vs))
+		for i, val := range vs {
+			s[i] = val
+		}
+		v.Names = s
+	}
+
+	// Params.Page.Num `gbind:"http.query.page,default=1"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	vs = query["page"]
+	if len(vs) == 0 {
+		vs = []string{"1"}
+	}
+	if len(vs) > 0 {
+		b, err := gbind.ParseInt(vs[0], 0)
+		if err != nil {
+			return err
+		}
+		v.Page.Num = int(b)
+	}
+
+	// Params.Page.Size `gbind:"http.query.size,default=10"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.Page.Size == nil {
+		v.Page.Size = new(int)
+	}
+	vs = query["size"]
+	if len(vs) == 0 {
+		vs = []string{"10"}
+	}
+	if len(vs) > 0 {
+		b, err := gbind.ParseInt(vs[0], 0)
+		if err != nil {
+			return err
+		}
+		*v.Page.Size = int(b)
+	}
+
+	// Params.Next.Num `gbind:"http.query.page,default=1"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.Next == nil {
+		v.Next = new(Page)
+	}
+	vs = query["page"]
+	if len(vs) == 0 {
+		vs = []string{"1"}
+	}
+	if len(vs) > 0 {
+		b, err := gbind.ParseInt(vs[0], 0)
+		if err != nil {
+			return err
+		}
+		v.Next.Num = int(b)
+	}
+
+	// Params.Next.Size `gbind:"http.query.size,default=10"`
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v.Next == nil {
+		v.Next = new(Page)
+	}
+	if v.Next.Size == nil {
+		v.Next.Size = new(int)
+	}
+	vs = query["size"]
+	if len(vs) == 0 {
+		vs = []string{"10"}
+	}
+	if len(vs) > 0 {
+		b, err := gbind.ParseInt(vs[0], 0)
+		if err != nil {
+			return err
+		}
+		*v.Next.Size = int(b)
+	}
+	return nil
+}
+
+// BindHTTP sets the fields of Empty bound by the http sources of the gbind tag, it implements gbind.HTTPBinder
+func (v *Empty) BindHTTP(ctx context.Context, r *http.Request) error {
+	if r == nil {
+		return gbind.ErrNilRequest
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package gentest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bdjimmy/gbind"
+	"github.com/stretchr/testify/assert"
+)
+
+// reflectParams has the same fields as Params without the generated BindHTTP
+type reflectParams Params
+
+func newRequest(query string, form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/test?"+query, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Timeout", "3s")
+	r.Header.Set("Names", "a, b ,c")
+	r.AddCookie(&http.Cookie{Name: "Token", Value: "foo-bar"})
+	return r
+}
+
+func TestGeneratedBinding(t *testing.T) {
+	query := "appkey=%20ABC%20&pair=3&pair=4&ratio=0.5&debug=true&status=2&page=3" +
+		"&since=2022-05-01T08:00:00Z&until=2022-06-01T08:00:00Z&addr=127.0.0.1&addr=::1" +
+		"&tag[]=go&tag[]=http&id[1]=2&id[0]=1"
+	form := url.Values{"uids": {"1,2,3"}}
+
+	expect := &reflectParams{}
+	_, err := gbind.BindWithValidate(context.Background(), expect, newRequest(query, form))
+	assert.Nil(t, err)
+
+	p := &Params{}
+	_, err = gbind.BindWithValidate(context.Background(), p, newRequest(query, form))
+	assert.Nil(t, err)
+	assert.Equal(t, expect, (*reflectParams)(p))
+
+	assert.Equal(t, "/api/test", p.API)
+	assert.Equal(t, "abc", p.Appkey)
+	assert.Equal(t, "foo-bar", *p.Token)
+	assert.Equal(t, "www.baidu.com", p.Host)
+	assert.Equal(t, []int64{1, 2, 3}, p.Uids)
+	assert.Equal(t, [2]uint8{3, 4}, p.Pair)
+	assert.Equal(t, Status(2), p.Status)
+	assert.Equal(t, 3, p.Page.Num)
+	assert.Equal(t, 10, *p.Next.Size)
+	assert.Equal(t, time.Date(2022, 5, 1, 8, 0, 0, 0, time.UTC), p.Since)
+	assert.Equal(t, time.Date(2022, 6, 1, 8, 0, 0, 0, time.UTC), *p.Until)
+	assert.Equal(t, []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}, p.Addrs)
+	assert.Equal(t, []string{"GO", "HTTP"}, p.Tags)
+	assert.Equal(t, []int{1, 2}, p.Ids)
+	assert.Equal(t, []string{"a", "b", "c"}, p.Names)
+
+	// called directly
+	p = &Params{}
+	assert.Nil(t, p.BindHTTP(context.Background(), newRequest(query, form)))
+	assert.Equal(t, expect, (*reflectParams)(p))
+
+	assert.Nil(t, (&Empty{}).BindHTTP(context.Background(), newRequest("", nil)))
+	assert.Equal(t, gbind.ErrNilRequest, p.BindHTTP(context.Background(), nil))
+
+	// the fields are not bound after the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p = &Params{}
+	assert.Equal(t, context.Canceled, p.BindHTTP(ctx, newRequest(query, form)))
+	assert.Empty(t, p.Appkey)
+}
+
+func TestGeneratedBindingErrors(t *testing.T) {
+	for query, form := range map[string]url.Values{
+		"pair=1":       nil,
+		"ratio=x":      nil,
+		"debug=2":      nil,
+		"status=1.5":   nil,
+		"since=x":      nil,
+		"addr=x":       nil,
+		"tag[]=golang": nil,
+		"":             {"uids": {"1,2,3,4"}},
+	} {
+		_, expect := gbind.Bind(context.Background(), &reflectParams{}, newRequest(query, form))
+		assert.NotNil(t, expect, query)
+		_, err := gbind.Bind(context.Background(), &Params{}, newRequest(query, form))
+		assert.Equal(t, strings.Replace(expect.Error(), "reflectParams", "Params", 1), err.Error(), query)
+	}
+}
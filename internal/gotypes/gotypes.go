@@ -0,0 +1,67 @@
+// Package gotypes checks the go/types of the fields like the reflection of gbind,
+// it is shared by the gbindvet analyzer and the gbindgen generator
+package gotypes
+
+import "go/types"
+
+// IsDuration reports whether typ is time.Duration, which is parsed by time.ParseDuration
+func IsDuration(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+}
+
+// textUnmarshaler the method set of encoding.TextUnmarshaler
+var textUnmarshaler = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(0, nil, "UnmarshalText", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(0, nil, "text", types.NewSlice(types.Typ[types.Byte]))),
+		types.NewTuple(types.NewVar(0, nil, "", types.Universe.Lookup("error").Type())), false)),
+}, nil).Complete()
+
+// IsTextUnmarshaler reports whether the pointer to typ implements encoding.TextUnmarshaler,
+// the same as the fields bound by UnmarshalText in gbind, e.g. time.Time and net.IP
+func IsTextUnmarshaler(typ types.Type) bool {
+	if _, ok := typ.Underlying().(*types.Pointer); ok {
+		return false
+	}
+	return types.Implements(types.NewPointer(typ), textUnmarshaler)
+}
+
+// IsCollected reports whether the values of typ are collected by the collection format,
+// the same as the slice and array fields of gbind which are not bound by UnmarshalText
+func IsCollected(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		return !IsTextUnmarshaler(typ)
+	}
+	return false
+}
+
+// BitSize the bit size of the basic kind parsed by gbind.TrySet, 0 for int and uint
+func BitSize(kind types.BasicKind) int {
+	switch kind {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Float64:
+		return 64
+	}
+	return 0
+}
+
+// Deref returns the element type of the pointers
+func Deref(typ types.Type) types.Type {
+	for {
+		ptr, ok := typ.Underlying().(*types.Pointer)
+		if !ok {
+			return typ
+		}
+		typ = ptr.Elem()
+	}
+}
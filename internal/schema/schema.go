@@ -0,0 +1,332 @@
+// Package schema builds the JSON Schema of the Go types and the validate rules,
+// it is shared by the OpenAPI and the JSON Schema generation of gbind
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DescTag the tag of the descriptions
+const DescTag = "desc"
+
+// Draft the $schema of the JSON Schema
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema a JSON Schema draft 2020-12, which is also the Schema Object of OpenAPI 3.1
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Walker builds the schemas of the json body, the validate rules of the fields are read from ValidateTag
+type Walker struct {
+	ValidateTag string
+	// DescTag the tag of the descriptions of the fields
+	DescTag string
+	// Skip reports whether the field is not a property of the json body
+	Skip func(reflect.StructField) bool
+	// visiting the structs being walked, the recursive types are not expanded again
+	visiting map[reflect.Type]bool
+}
+
+// JSON returns the schema of t decoded by encoding/json
+func (w *Walker) JSON(t reflect.Type) *Schema {
+	if w.visiting == nil {
+		w.visiting = map[reflect.Type]bool{}
+	}
+	t = Deref(t)
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawJSONType || t.Kind() == reflect.Interface ||
+		t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return w.object(t)
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: w.JSON(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		s := &Schema{Type: "array", Items: w.JSON(t.Elem())}
+		if t.Kind() == reflect.Array {
+			s.MinItems, s.MaxItems = intPtr(t.Len()), intPtr(t.Len())
+		}
+		return s
+	}
+	return Basic(t)
+}
+
+// object the properties of the struct like encoding/json, the embedded structs are inlined
+func (w *Walker) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object"}
+	if w.visiting[t] {
+		return s
+	}
+	w.visiting[t] = true
+	defer delete(w.visiting, t)
+
+	s.Properties = map[string]*Schema{}
+	w.properties(t, s)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	return s
+}
+
+func (w *Walker) properties(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := Name(field)
+		switch {
+		case !ok || w.Skip != nil && w.Skip(field):
+			continue
+		case name == "":
+			w.properties(Deref(field.Type), s)
+			continue
+		}
+		fs, required := w.Property(field)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// Name returns the name of the field in json, it is empty if the embedded struct is inlined,
+// ok is false if the field is not decoded by encoding/json
+func Name(field reflect.StructField) (name string, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _ = head(tag, ",")
+	if field.Anonymous && name == "" && Deref(field.Type).Kind() == reflect.Struct {
+		return "", true
+	}
+	if field.PkgPath != "" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// Property returns the schema of the field with its description and validate rules, and whether it is required
+func (w *Walker) Property(field reflect.StructField) (*Schema, bool) {
+	fs := w.JSON(field.Type)
+	_, opts := head(field.Tag.Get("json"), ",")
+	if strings.Contains(","+opts+",", ",string,") && fs.Type != "" && fs.Type != "object" && fs.Type != "array" {
+		fs = &Schema{Type: "string"}
+	}
+	w.Describe(fs, field)
+	return fs, Apply(fs, field.Tag.Get(w.ValidateTag))
+}
+
+// Describe sets the description of the field to s
+func (w *Walker) Describe(s *Schema, field reflect.StructField) {
+	if w.DescTag == "" {
+		return
+	}
+	if desc := field.Tag.Get(w.DescTag); desc != "" {
+		s.Description = desc
+	}
+}
+
+// Basic returns the schema of the basic types
+func Basic(t reflect.Type) *Schema {
+	t = Deref(t)
+	if t == durationType {
+		return &Schema{Type: "integer", Format: "int64"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: Basic(t.Elem())}
+	}
+	return &Schema{}
+}
+
+// formats the validate rules which are the formats of the strings
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// Apply maps the validate rules to the constraints of s, and reports whether the value is required,
+// the rules behind `dive` are applied to the items, the rules which can not be mapped are ignored
+func Apply(s *Schema, rules string) (required bool) {
+	if rules == "" || rules == "-" {
+		return false
+	}
+	parts := strings.Split(rules, ",")
+	for i, rule := range parts {
+		name, param := head(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			switch {
+			case s.Items != nil:
+				Apply(s.Items, strings.Join(parts[i+1:], ","))
+			case s.AdditionalProperties != nil:
+				Apply(s.AdditionalProperties, strings.Join(parts[i+1:], ","))
+			}
+			return required
+		case "min", "gte":
+			s.setMin(param, false)
+		case "max", "lte":
+			s.setMax(param, false)
+		case "gt":
+			s.setMin(param, true)
+		case "lt":
+			s.setMax(param, true)
+		case "len":
+			s.setMin(param, false)
+			s.setMax(param, false)
+		case "oneof":
+			s.Enum = nil
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, s.Value(v))
+			}
+		default:
+			if f, ok := formats[name]; ok && s.Type == "string" {
+				s.Format = f
+			}
+		}
+	}
+	return required
+}
+
+func (s *Schema) setMin(param string, exclusive bool) {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	n := int(f)
+	if exclusive {
+		n++
+	}
+	switch s.Type {
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMinimum = &f
+		} else {
+			s.Minimum = &f
+		}
+	case "string":
+		s.MinLength = &n
+	case "array":
+		s.MinItems = &n
+	}
+}
+
+func (s *Schema) setMax(param string, exclusive bool) {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	n := int(f)
+	if exclusive {
+		n--
+	}
+	switch s.Type {
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMaximum = &f
+		} else {
+			s.Maximum = &f
+		}
+	case "string":
+		s.MaxLength = &n
+	case "array":
+		s.MaxItems = &n
+	}
+}
+
+// Value converts the string to the value of the type of s, it is the string itself if it can not be converted
+func (s *Schema) Value(v string) interface{} {
+	switch s.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// Deref returns the type pointed to
+func Deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func head(str, sep string) (string, string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
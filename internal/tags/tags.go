@@ -0,0 +1,124 @@
+// Package tags parses the struct tags of gbind,
+// it is shared by the runtime, the gbindvet analyzer and the gbindgen generator
+package tags
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// HTTPArity the number of the dot separated parts of the http sources
+var HTTPArity = map[string]int{
+	"path":   2, // http.path
+	"query":  3, // http.query.appkey
+	"form":   3, // http.form.uids
+	"header": 3, // http.header.host
+	"cookie": 3, // http.cookie.Token
+}
+
+// Transforms the names of the built-in transforms of the bind tag
+var Transforms = []string{"trim", "lower", "upper"}
+
+// Option a key=value option behind the source of the bind tag
+type Option struct {
+	Key   string
+	Value string
+}
+
+// Split splits the bind tag into the source and its options,
+// `split=,` is a special case which uses the comma as the separator, also at the end of the tag
+func Split(tag string) (string, []Option) {
+	source, tail := head(tag, ",")
+	var (
+		left string
+		opts []Option
+	)
+	for len(tail) > 0 {
+		left, tail = head(tail, ",")
+		k, v := head(left, "=")
+		if k == "split" && v == "" {
+			switch {
+			case strings.HasPrefix(tail, ","):
+				v, tail = ",", tail[1:]
+			case tail == "":
+				v = ","
+			}
+		}
+		opts = append(opts, Option{Key: k, Value: v})
+	}
+	return source, opts
+}
+
+// Pair a key and its unquoted value of a struct tag
+type Pair struct {
+	Key   string
+	Value string
+}
+
+// Pairs returns the keys and the values of the tag in order,
+// the same as reflect.StructTag.Lookup but iterates all of the keys
+func Pairs(tag reflect.StructTag) []Pair {
+	var pairs []Pair
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := string(tag[:i])
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+		pairs = append(pairs, Pair{Key: key, Value: value})
+	}
+	return pairs
+}
+
+// IsRuleName reports whether s can be the name of a validate rule,
+// e.g. required of `err_msg:"required=please login"`
+func IsRuleName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func head(str, sep string) (string, string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
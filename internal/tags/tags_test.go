@@ -0,0 +1,55 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	for testName, tt := range map[string]struct {
+		tag    string
+		source string
+		opts   []Option
+	}{
+		"source-only": {
+			"http.query.id", "http.query.id", nil,
+		},
+		"default": {
+			"http.query.id,default=1", "http.query.id", []Option{{"default", "1"}},
+		},
+		"split-comma": {
+			"http.query.ids,split=,,trim", "http.query.ids", []Option{{"split", ","}, {"trim", ""}},
+		},
+		"split-comma-end": {
+			"http.query.ids,trim,split=,", "http.query.ids", []Option{{"trim", ""}, {"split", ","}},
+		},
+		"split-other": {
+			"http.query.ids,trim,split=|", "http.query.ids", []Option{{"trim", ""}, {"split", "|"}},
+		},
+	} {
+		source, opts := Split(tt.tag)
+		assert.Equal(t, tt.source, source, testName)
+		assert.Equal(t, tt.opts, opts, testName)
+	}
+}
+
+func TestPairs(t *testing.T) {
+	tag := reflect.StructTag(`gbind:"http.query.name" err_msg:"a \"b\"" err_msg_zh:"中文"`)
+	assert.Equal(t, []Pair{
+		{"gbind", "http.query.name"},
+		{"err_msg", `a "b"`},
+		{"err_msg_zh", "中文"},
+	}, Pairs(tag))
+
+	// stops at the malformed pair like reflect.StructTag.Lookup
+	assert.Equal(t, []Pair{{"a", "1"}}, Pairs(`a:"1" b:2 c:"3"`))
+}
+
+func TestIsRuleName(t *testing.T) {
+	assert.True(t, IsRuleName("required_if"))
+	assert.True(t, IsRuleName("is-awesome"))
+	assert.False(t, IsRuleName(""))
+	assert.False(t, IsRuleName("please login"))
+}
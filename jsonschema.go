@@ -0,0 +1,129 @@
+package gbind
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bdjimmy/gbind/internal/schema"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Schema a JSON Schema draft 2020-12
+type Schema = schema.Schema
+
+// JSONSchema returns the JSON Schema of the json body bound to v by the package functions
+func JSONSchema(v interface{}) (*Schema, error) {
+	return defaultGbind.JSONSchema(v)
+}
+
+// JSONSchema returns the JSON Schema draft 2020-12 of the json body bound to v, v is a pointer to the struct
+// or a pointer to a slice, array or map of structs, a nil pointer is enough.
+//
+// The properties are named by the json tag, the validate rules required, gte, lte, min, max, len, oneof, email
+// and the rules behind dive are the constraints, and the `desc` tag is the description,
+// the fields bound by the bind tag without the json tag are not the properties
+func (g *Gbind) JSONSchema(v interface{}) (*Schema, error) {
+	st, err := g.compileType(v)
+	if err != nil {
+		return nil, err
+	}
+	w := &schema.Walker{
+		ValidateTag: g.options.validateTagName,
+		DescTag:     schema.DescTag,
+		Skip:        g.notJSONField,
+	}
+	rt := reflect.TypeOf(v).Elem()
+	elem := rt
+	if st.elem {
+		elem = deref(rt.Elem())
+	}
+	s := st.jsonObject(w, elem.Name())
+	if st.elem && rt.Kind() == reflect.Map {
+		s = &Schema{Type: "object", AdditionalProperties: s}
+	} else if st.elem {
+		s = &Schema{Type: "array", Items: s}
+	}
+	s.Schema = schema.Draft
+	return s, nil
+}
+
+// notJSONField the fields bound by the bind tag are not decoded from the json body unless they have the json tag
+func (g *Gbind) notJSONField(field reflect.StructField) bool {
+	_, bound := field.Tag.Lookup(g.options.bindTagName)
+	_, decoded := field.Tag.Lookup("json")
+	return bound && !decoded
+}
+
+// jsonObject builds the object by the namespaces of deepTraverse, Req.Address.City is the property city
+// of the property address, the slices, arrays and maps of the fields are walked by w
+func (sv *structType) jsonObject(w *schema.Walker, ns string) *Schema {
+	root := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// namespace => the object of the struct, nil if it is not an object of the json
+	objects := map[string]*Schema{ns: root}
+	// the structs and the fields in the order of the struct, the parents are before their fields
+	fields := append(append([]*fieldInfo{}, sv.structFields...), sv.fieldList...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	for _, f := range fields {
+		parent := objects[parentNamespace(f)]
+		name, ok := schema.Name(f.structField)
+		if parent == nil || !ok || w.Skip(f.structField) {
+			objects[f.namespace] = nil
+			continue
+		}
+		t := deref(f.structField.Type)
+		switch {
+		case t.Kind() != reflect.Struct || isJSONValue(t):
+			// the fields and the structs decoded as a value, e.g. time.Time
+			objects[f.namespace] = nil
+			sv.jsonProperty(w, parent, name, f.structField)
+		case name == "":
+			// the embedded struct is inlined
+			objects[f.namespace] = parent
+		default:
+			child := &Schema{Type: "object", Properties: map[string]*Schema{}}
+			w.Describe(child, f.structField)
+			if schema.Apply(child, f.structField.Tag.Get(w.ValidateTag)) {
+				parent.Required = append(parent.Required, name)
+			}
+			parent.Properties[name] = child
+			objects[f.namespace] = child
+		}
+	}
+	if len(root.Properties) == 0 {
+		root.Properties = nil
+	}
+	return root
+}
+
+func (sv *structType) jsonProperty(w *schema.Walker, obj *Schema, name string, field reflect.StructField) {
+	s, required := w.Property(field)
+	if required {
+		obj.Required = append(obj.Required, name)
+	}
+	obj.Properties[name] = s
+}
+
+// isJSONValue reports whether the struct is decoded as a value rather than an object
+func isJSONValue(t reflect.Type) bool {
+	t = reflect.PtrTo(deref(t))
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType)
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// parentNamespace Req.Address.City => Req.Address
+func parentNamespace(f *fieldInfo) string {
+	return f.namespace[:strings.LastIndex(f.namespace, ".")]
+}
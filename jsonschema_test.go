@@ -0,0 +1,97 @@
+package gbind
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaAddress struct {
+	City string `json:"city" validate:"required" desc:"the city"`
+	Zip  string `json:"zip,omitempty" validate:"len=6"`
+}
+
+type schemaBase struct {
+	ID int64 `json:"id" validate:"gt=0"`
+}
+
+type schemaParams struct {
+	schemaBase
+	Appkey    string            `gbind:"http.query.appkey"`
+	Name      string            `gbind:"http.form.name" json:"name" validate:"required,max=16"`
+	Email     string            `json:"email" validate:"required,email" desc:"the email"`
+	Age       *int              `json:"age" validate:"gte=1,lte=130"`
+	Sort      string            `json:"sort" validate:"oneof=asc desc"`
+	Tags      []string          `json:"tags" validate:"max=8,dive,min=1"`
+	Scores    map[string]uint32 `json:"scores"`
+	Address   *schemaAddress    `json:"address" validate:"required" desc:"the address"`
+	CreatedAt time.Time         `json:"created_at"`
+	Count     int               `json:"count,string"`
+	Ignored   string            `json:"-"`
+	secret    string
+}
+
+func TestJSONSchema(t *testing.T) {
+	s, err := JSONSchema(&schemaParams{})
+	assert.Nil(t, err)
+	bs, err := json.Marshal(s)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer", "format": "int64", "exclusiveMinimum": 0},
+    "name": {"type": "string", "maxLength": 16},
+    "email": {"type": "string", "format": "email", "description": "the email"},
+    "age": {"type": "integer", "format": "int64", "minimum": 1, "maximum": 130},
+    "sort": {"type": "string", "enum": ["asc", "desc"]},
+    "tags": {"type": "array", "maxItems": 8, "items": {"type": "string", "minLength": 1}},
+    "scores": {"type": "object", "additionalProperties": {"type": "integer", "format": "int32"}},
+    "address": {"type": "object", "description": "the address", "properties": {
+      "city": {"type": "string", "description": "the city"},
+      "zip": {"type": "string", "minLength": 6, "maxLength": 6}
+    }, "required": ["city"]},
+    "created_at": {"type": "string", "format": "date-time"},
+    "count": {"type": "string"}
+  },
+  "required": ["name", "email", "address"]
+}`, string(bs))
+
+	// a nil pointer is enough
+	var p *schemaParams
+	s2, err := JSONSchema(p)
+	assert.Nil(t, err)
+	assert.Equal(t, s, s2)
+}
+
+func TestJSONSchemaElem(t *testing.T) {
+	s, err := JSONSchema(&[]*schemaAddress{})
+	assert.Nil(t, err)
+	assert.Equal(t, "array", s.Type)
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", s.Schema)
+	assert.Equal(t, []string{"city"}, s.Items.Required)
+	assert.Equal(t, "", s.Items.Schema)
+
+	s, err = JSONSchema(&map[string]schemaAddress{})
+	assert.Nil(t, err)
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, []string{"city"}, s.AdditionalProperties.Required)
+}
+
+func TestJSONSchemaOptions(t *testing.T) {
+	type params struct {
+		Page int    `bind:"http.query.page"`
+		Name string `json:"name" check:"required,min=2"`
+	}
+	g := NewGbind(WithBindTag("bind"), WithValidateTag("check"))
+	s, err := g.JSONSchema(&params{})
+	assert.Nil(t, err)
+	assert.Len(t, s.Properties, 1)
+	assert.Equal(t, 2, *s.Properties["name"].MinLength)
+	assert.Equal(t, []string{"name"}, s.Required)
+
+	_, err = g.JSONSchema(params{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,333 @@
+package gbind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// the names of the limits
+const (
+	LimitBodyBytes      = "body_bytes"
+	LimitDepth          = "depth"
+	LimitMultipartFiles = "multipart_files"
+	LimitSliceLen       = "slice_len"
+	LimitStringLen      = "string_len"
+)
+
+// ErrLimitExceeded matches every *LimitError by errors.Is
+var ErrLimitExceeded = errors.New("gbind: limit exceeded")
+
+// LimitError is returned when a limit of the binding is exceeded
+type LimitError struct {
+	// Limit the name of the limit, e.g. LimitBodyBytes
+	Limit string
+	// Max the maximum of the limit
+	Max int64
+	// Field the namespace of the field, empty for the limits of the request
+	Field string
+}
+
+func (err *LimitError) Error() string {
+	if err.Field == "" {
+		return fmt.Sprintf("gbind: %s exceeds the limit %d", err.Limit, err.Max)
+	}
+	return fmt.Sprintf("gbind: %s of %s exceeds the limit %d", err.Limit, err.Field, err.Max)
+}
+
+// Is reports whether target is ErrLimitExceeded
+func (err *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// limits of the binding, zero means no limit
+type limits struct {
+	// maxBodyBytes the maximum bytes of the request body
+	maxBodyBytes int64
+	// maxMultipartMemory the maximum memory of the multipart form, the rest is stored on disk
+	maxMultipartMemory int64
+	// maxMultipartFiles the maximum number of the multipart files
+	maxMultipartFiles int
+	// maxSliceLen the maximum length of the slice fields, can be overridden by max_items=
+	maxSliceLen int
+	// maxStringLen the maximum length of every value of the fields, can be overridden by max_len=
+	maxStringLen int
+	// maxDepth the maximum nesting depth of the json body
+	maxDepth int
+}
+
+// WithMaxBodyBytes limits the bytes of the request body read by the json and form binding,
+// the body of the request is replaced by a limited reader during the binding
+func WithMaxBodyBytes(n int64) OptApply {
+	return func(opt *options) {
+		opt.limits.maxBodyBytes = n
+	}
+}
+
+// WithMaxMultipartMemory changes the maximum memory of the multipart form, 32 MB by default
+func WithMaxMultipartMemory(n int64) OptApply {
+	return func(opt *options) {
+		opt.limits.maxMultipartMemory = n
+	}
+}
+
+// WithMaxMultipartFiles limits the number of the multipart files, the parsing of the form stops
+// when the file exceeding the limit starts
+func WithMaxMultipartFiles(n int) OptApply {
+	return func(opt *options) {
+		opt.limits.maxMultipartFiles = n
+	}
+}
+
+// WithMaxSliceLen limits the length of the slice fields,
+// it can be overridden by the field like `gbind:"http.query.uids,max_items=100"`
+func WithMaxSliceLen(n int) OptApply {
+	return func(opt *options) {
+		opt.limits.maxSliceLen = n
+	}
+}
+
+// WithMaxStringLen limits the length of every value bound to the fields,
+// it can be overridden by the field like `gbind:"http.query.name,max_len=64"`
+func WithMaxStringLen(n int) OptApply {
+	return func(opt *options) {
+		opt.limits.maxStringLen = n
+	}
+}
+
+// WithMaxDepth limits the nesting depth of the json body
+func WithMaxDepth(n int) OptApply {
+	return func(opt *options) {
+		opt.limits.maxDepth = n
+	}
+}
+
+// limitedBody is similar to http.MaxBytesReader, the LimitError is kept
+// because the errors of the form parsing are not returned
+type limitedBody struct {
+	io.ReadCloser
+	n   int64
+	max int64
+	err *LimitError
+}
+
+func newLimitedBody(body io.ReadCloser, max int64) *limitedBody {
+	return &limitedBody{ReadCloser: body, n: max, max: max}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// read one more byte to know whether the limit is exceeded
+	if int64(len(p))-1 > b.n {
+		p = p[:b.n+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.n {
+		b.n -= int64(n)
+		return n, err
+	}
+	n = int(b.n)
+	b.n = 0
+	b.err = &LimitError{Limit: LimitBodyBytes, Max: b.max}
+	return n, b.err
+}
+
+// limitBody wraps the body of req if the body bytes are limited until unwrap is called,
+// nil if the body is not wrapped
+func (l *limits) limitBody(req *http.Request) *limitedBody {
+	if l.maxBodyBytes <= 0 || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if _, ok := req.Body.(*limitedBody); ok {
+		return nil
+	}
+	b := newLimitedBody(req.Body, l.maxBodyBytes)
+	req.Body = b
+	return b
+}
+
+// unwrap restores the body of req replaced by b
+func (b *limitedBody) unwrap(req *http.Request) {
+	if req.Body == io.ReadCloser(b) {
+		req.Body = b.ReadCloser
+	}
+}
+
+// multipartFiles counts the files of the multipart body by multipart.Reader, the parts are written again
+// to the form read by ReadForm, the reading stops with the LimitError when the part of a file exceeding
+// the limit starts, so that the files are not read to the memory or the disk
+type multipartFiles struct {
+	r *multipart.Reader
+	w *multipart.Writer
+	// buf the parts written again which are not read yet
+	buf  bytes.Buffer
+	part *multipart.Part
+	pw   io.Writer
+	n    int
+	max  int
+	// err the error of reading the body, io.EOF after the last part, or the *LimitError
+	err error
+}
+
+// countMultipartFiles returns the counter of the files if the body of req is a multipart form and the files are limited,
+// nil if the form is parsed by ParseMultipartForm
+func (l *limits) countMultipartFiles(req *http.Request) *multipartFiles {
+	if l.maxMultipartFiles <= 0 || req.Body == nil || req.Body == http.NoBody || req.MultipartForm != nil {
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil
+	}
+	b := &multipartFiles{
+		r:   multipart.NewReader(req.Body, params["boundary"]),
+		max: l.maxMultipartFiles,
+	}
+	b.w = multipart.NewWriter(&b.buf)
+	return b
+}
+
+func (b *multipartFiles) Read(p []byte) (int, error) {
+	for b.buf.Len() == 0 && b.err == nil {
+		b.next()
+	}
+	if b.buf.Len() > 0 {
+		return b.buf.Read(p)
+	}
+	return 0, b.err
+}
+
+// next writes the header of the next part or a chunk of the current part to buf,
+// the files are counted like multipart.Reader.ReadForm, which skips the parts without a name
+func (b *multipartFiles) next() {
+	if b.part == nil {
+		part, err := b.r.NextRawPart()
+		if err == io.EOF {
+			b.w.Close()
+			b.err = io.EOF
+			return
+		}
+		if err != nil {
+			b.err = err
+			return
+		}
+		if part.FormName() != "" && part.FileName() != "" {
+			if b.n++; b.n > b.max {
+				b.err = &LimitError{Limit: LimitMultipartFiles, Max: int64(b.max)}
+				return
+			}
+		}
+		b.part = part
+		b.pw, _ = b.w.CreatePart(part.Header)
+		return
+	}
+	if _, err := io.CopyN(b.pw, b.part, 32<<10); err == io.EOF {
+		b.part = nil
+	} else if err != nil {
+		b.err = err
+	}
+}
+
+// limitErr returns the LimitError if the files exceed the limit
+func (b *multipartFiles) limitErr() *LimitError {
+	err, _ := b.err.(*LimitError)
+	return err
+}
+
+// parseForm is the same as req.ParseMultipartForm, but the form is read from the counted parts
+func (b *multipartFiles) parseForm(req *http.Request, maxMemory int64) {
+	// parses the query to req.Form, PostForm is empty for the multipart form
+	if err := req.ParseForm(); err != nil {
+		return
+	}
+	form, err := multipart.NewReader(b, b.w.Boundary()).ReadForm(maxMemory)
+	if err != nil {
+		return
+	}
+	for k, v := range form.Value {
+		req.Form[k] = append(req.Form[k], v...)
+		req.PostForm[k] = append(req.PostForm[k], v...)
+	}
+	req.MultipartForm = form
+}
+
+// bodyErr returns the LimitError of the body if the limit is exceeded,
+// or the error of the context if the binding is cancelled while reading the body
+func bodyErr(req *http.Request) error {
+	body := req.Body
+	for {
+		switch b := body.(type) {
+		case *limitedBody:
+			if b.err != nil {
+				return b.err
+			}
+			body = b.ReadCloser
+		case *contextBody:
+			if b.err != nil {
+				return b.err
+			}
+			body = b.ReadCloser
+		default:
+			return nil
+		}
+	}
+}
+
+// depthReader checks the nesting depth of the json while it is read
+type depthReader struct {
+	r        io.Reader
+	max      int
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+func (d *depthReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	for _, c := range p[:n] {
+		switch {
+		case d.escaped:
+			d.escaped = false
+		case d.inString:
+			if c == '\\' {
+				d.escaped = true
+			} else if c == '"' {
+				d.inString = false
+			}
+		case c == '"':
+			d.inString = true
+		case c == '{' || c == '[':
+			d.depth++
+			if d.depth > d.max {
+				return 0, &LimitError{Limit: LimitDepth, Max: int64(d.max)}
+			}
+		case c == '}' || c == ']':
+			d.depth--
+		}
+	}
+	return n, err
+}
+
+// checkValues checks the count and the length of the values bound to the field
+func (opt *DefaultOption) checkValues(vs []string, isSlice bool) error {
+	if isSlice && opt.maxItems > 0 && len(vs) > opt.maxItems {
+		return &LimitError{Limit: LimitSliceLen, Max: int64(opt.maxItems), Field: opt.namespace}
+	}
+	if opt.maxLen > 0 {
+		for _, v := range vs {
+			if len(v) > opt.maxLen {
+				return &LimitError{Limit: LimitStringLen, Max: int64(opt.maxLen), Field: opt.namespace}
+			}
+		}
+	}
+	return nil
+}
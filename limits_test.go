@@ -0,0 +1,225 @@
+package gbind
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func multipartReq(files int, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	for i := 0; i < files; i++ {
+		fw, _ := w.CreateFormFile("file", "f.txt")
+		fw.Write([]byte("content"))
+	}
+	w.Close()
+	req, _ := http.NewRequest(http.MethodPost, "http://www.test.com/api/test", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestMultipartFiles(t *testing.T) {
+	req := multipartReq(3, map[string]string{"name": "a"})
+	req.URL.RawQuery = "q=b"
+	b := (&limits{maxMultipartFiles: 3}).countMultipartFiles(req)
+	if assert.NotNil(t, b) {
+		b.parseForm(req, 1<<10)
+		assert.Equal(t, 3, b.n)
+		assert.Nil(t, b.limitErr())
+		if assert.NotNil(t, req.MultipartForm) {
+			assert.Len(t, req.MultipartForm.File["file"], 3)
+			f, err := req.MultipartForm.File["file"][2].Open()
+			if assert.Nil(t, err) {
+				bs, _ := io.ReadAll(f)
+				assert.Equal(t, "content", string(bs))
+				f.Close()
+			}
+		}
+		assert.Equal(t, url.Values{"name": {"a"}}, req.PostForm)
+		assert.Equal(t, url.Values{"name": {"a"}, "q": {"b"}}, req.Form)
+	}
+
+	// the file without a name is skipped like ReadForm
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fw, _ := w.CreatePart(textproto.MIMEHeader{"Content-Disposition": {`form-data; filename="f.txt"`}})
+	fw.Write([]byte("content"))
+	fw, _ = w.CreateFormFile("file", "f.txt")
+	fw.Write([]byte("content"))
+	w.Close()
+	req, _ = http.NewRequest(http.MethodPost, "http://www.test.com/api/test", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	b = (&limits{maxMultipartFiles: 1}).countMultipartFiles(req)
+	if assert.NotNil(t, b) {
+		b.parseForm(req, 1<<10)
+		assert.Nil(t, b.limitErr())
+		assert.Len(t, req.MultipartForm.File["file"], 1)
+	}
+
+	req = multipartReq(2, nil)
+	b = (&limits{maxMultipartFiles: 1}).countMultipartFiles(req)
+	if assert.NotNil(t, b) {
+		b.parseForm(req, 1<<10)
+		assert.Equal(t, LimitMultipartFiles, b.limitErr().Limit)
+		assert.Nil(t, req.MultipartForm)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "http://www.test.com/api/test", strings.NewReader("name=a"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Nil(t, (&limits{maxMultipartFiles: 3}).countMultipartFiles(req))
+}
+
+func assertLimitErr(t *testing.T, err error, limit string, field string) {
+	assert.True(t, errors.Is(err, ErrLimitExceeded), "%v", err)
+	var limitErr *LimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, limit, limitErr.Limit)
+		assert.Equal(t, field, limitErr.Field)
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	b := newLimitedBody(io.NopCloser(strings.NewReader("12345")), 5)
+	bs, err := io.ReadAll(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "12345", string(bs))
+
+	b = newLimitedBody(io.NopCloser(strings.NewReader("123456")), 5)
+	bs, err = io.ReadAll(b)
+	assert.NotNil(t, err)
+	assert.Equal(t, "12345", string(bs))
+	assert.Equal(t, LimitBodyBytes, b.err.Limit)
+}
+
+func TestLimits(t *testing.T) {
+	type JSONBody struct {
+		Name interface{} `json:"name"`
+	}
+
+	// body bytes of json
+	{
+		g := NewGbind(WithMaxBodyBytes(16))
+		_, err := g.Bind(context.Background(), &JSONBody{}, newReq().setBody(`{"name":"a"}`).r())
+		assert.Nil(t, err)
+
+		_, err = g.Bind(context.Background(), &JSONBody{}, newReq().setBody(`{"name":"abcdefghijklmn"}`).r())
+		assertLimitErr(t, err, LimitBodyBytes, "")
+	}
+
+	// body bytes of form
+	{
+		type Foo struct {
+			Name string `gbind:"http.form.name"`
+		}
+		g := NewGbind(WithMaxBodyBytes(64))
+		_, err := g.Bind(context.Background(), &Foo{}, multipartReq(0, map[string]string{"name": strings.Repeat("a", 128)}))
+		assertLimitErr(t, err, LimitBodyBytes, "")
+	}
+
+	// multipart files
+	{
+		type Foo struct {
+			Name string `gbind:"http.form.name"`
+		}
+		g := NewGbind(WithMaxMultipartFiles(2), WithMaxMultipartMemory(1<<10))
+		f := &Foo{}
+		_, err := g.Bind(context.Background(), f, multipartReq(2, map[string]string{"name": "a"}))
+		assert.Nil(t, err)
+		assert.Equal(t, "a", f.Name)
+
+		_, err = g.Bind(context.Background(), &Foo{}, multipartReq(3, map[string]string{"name": "a"}))
+		assertLimitErr(t, err, LimitMultipartFiles, "")
+	}
+
+	// the files exceeding the limit are not read
+	{
+		type Foo struct {
+			Name string `gbind:"http.form.name"`
+		}
+		body := &bytes.Buffer{}
+		w := multipart.NewWriter(body)
+		w.WriteField("name", "a")
+		for i := 0; i < 3; i++ {
+			fw, _ := w.CreateFormFile("file", "f.txt")
+			fw.Write(bytes.Repeat([]byte("x"), 1<<20))
+		}
+		w.Close()
+		size := body.Len()
+		counter := &countingReader{r: body}
+		req, _ := http.NewRequest(http.MethodPost, "http://www.test.com/api/test", counter)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		g := NewGbind(WithMaxMultipartFiles(2), WithMaxMultipartMemory(1<<10))
+		_, err := g.Bind(context.Background(), &Foo{}, req)
+		assertLimitErr(t, err, LimitMultipartFiles, "")
+		assert.Less(t, counter.n, size-1<<19)
+		assert.Equal(t, io.NopCloser(counter), req.Body)
+	}
+
+	// json depth
+	{
+		g := NewGbind(WithMaxDepth(2))
+		_, err := g.Bind(context.Background(), &JSONBody{}, newReq().setBody(`{"name":["[[{{"]}`).r())
+		assert.Nil(t, err)
+
+		_, err = g.Bind(context.Background(), &JSONBody{}, newReq().setBody(`{"name":[[1]]}`).r())
+		assertLimitErr(t, err, LimitDepth, "")
+	}
+
+	// slice and string length
+	{
+		type Foo struct {
+			Uids  []int    `gbind:"http.query.uids"`
+			Names []string `gbind:"http.query.names,max_items=3,max_len=2"`
+		}
+		g := NewGbind(WithMaxSliceLen(2), WithMaxStringLen(4))
+		_, err := g.Bind(context.Background(), &Foo{}, newReq().addQueryParam("uids", "1").addQueryParam("uids", "2").r())
+		assert.Nil(t, err)
+
+		req := newReq().addQueryParam("uids", "1").addQueryParam("uids", "2").addQueryParam("uids", "3").r()
+		_, err = g.Bind(context.Background(), &Foo{}, req)
+		assertLimitErr(t, err, LimitSliceLen, "Foo.Uids")
+
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().addQueryParam("uids", "12345").r())
+		assertLimitErr(t, err, LimitStringLen, "Foo.Uids")
+
+		req = newReq().addQueryParam("names", "a").addQueryParam("names", "b").addQueryParam("names", "c").r()
+		_, err = g.Bind(context.Background(), &Foo{}, req)
+		assert.Nil(t, err)
+
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().addQueryParam("names", "abc").r())
+		assertLimitErr(t, err, LimitStringLen, "Foo.Names")
+	}
+
+	// invalid tag
+	{
+		type Foo struct {
+			Uids []int `gbind:"http.query.uids,max_items=x"`
+		}
+		_, err := Bind(context.Background(), &Foo{}, newReq().r())
+		assert.NotNil(t, err)
+	}
+}
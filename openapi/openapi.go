@@ -0,0 +1,196 @@
+// Package openapi generates the OpenAPI 3.1 parameters and request body of the structs bound by gbind.
+//
+// The fields bound by the http sources are the parameters in query, path, header and cookie,
+// the fields bound by http.form are the properties of the form request body, and the fields decoded
+// from the json body are the properties of the json request body. The default= of the gbind tag
+// is the default of the schema, the validate rules required, min, max, len, oneof and so on are
+// mapped to the constraints, and the `desc` tag is the description:
+//
+//	type Params struct {
+//		Page int `gbind:"http.query.page,default=1" validate:"min=1" desc:"the page number"`
+//	}
+//
+//	op, err := openapi.Generate(nil, &Params{})
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bdjimmy/gbind"
+	"github.com/bdjimmy/gbind/internal/compiled"
+	"github.com/bdjimmy/gbind/internal/schema"
+)
+
+// DescTag the tag of the descriptions
+const DescTag = "desc"
+
+// the content types of the request bodies
+const (
+	ContentJSON      = "application/json"
+	ContentForm      = "application/x-www-form-urlencoded"
+	ContentMultipart = "multipart/form-data"
+)
+
+// Schema the Schema Object, which is a JSON Schema draft 2020-12
+type Schema = schema.Schema
+
+// Operation the parameters and the request body of an Operation Object
+type Operation struct {
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+}
+
+// Parameter the Parameter Object
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody the Request Body Object
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// MediaType the Media Type Object
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// the locations of the parameters by the Name() of the http execers
+var locations = map[string]string{
+	"http.query":  "query",
+	"http.path":   "path",
+	"http.head":   "header",
+	"http.cookie": "cookie",
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Generate returns the parameters and the request body of v compiled by g, v is a pointer to the struct,
+// the options of the package functions gbind.Bind and gbind.BindWithValidate are used if g is nil
+func Generate(g *gbind.Gbind, v interface{}) (*Operation, error) {
+	var gi interface{}
+	if g != nil {
+		gi = g
+	}
+	cs, err := compiled.Compile(gi, v)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &Operation{}
+	var form *Schema
+	for _, f := range cs.Fields {
+		if f.Source == "http.form" {
+			if form == nil {
+				form = &Schema{Type: "object", Properties: map[string]*Schema{}}
+			}
+			s, required := fieldSchema(f, cs.ValidateTag)
+			form.Properties[f.Param] = s
+			if required {
+				form.Required = append(form.Required, f.Param)
+			}
+			continue
+		}
+		in, ok := locations[f.Source]
+		if !ok {
+			continue
+		}
+		op.Parameters = append(op.Parameters, parameter(f, in, cs.ValidateTag))
+	}
+
+	if form != nil || cs.JSON {
+		op.RequestBody = &RequestBody{Content: map[string]*MediaType{}}
+	}
+	if form != nil {
+		op.RequestBody.Content[ContentForm] = &MediaType{Schema: form}
+		op.RequestBody.Content[ContentMultipart] = &MediaType{Schema: form}
+	}
+	if cs.JSON {
+		s, err := jsonSchema(g, v)
+		if err != nil {
+			return nil, err
+		}
+		// the Schema Object is not a schema resource
+		s.Schema = ""
+		op.RequestBody.Required = true
+		op.RequestBody.Content[ContentJSON] = &MediaType{Schema: s}
+	}
+	return op, nil
+}
+
+// parameter the parameter of the field bound by the http sources
+func parameter(f compiled.Field, in, validateTag string) *Parameter {
+	s, required := fieldSchema(f, validateTag)
+	p := &Parameter{
+		Name:        f.Param,
+		In:          in,
+		Description: s.Description,
+		Required:    required,
+		Schema:      s,
+	}
+	s.Description = ""
+	if in == "path" {
+		// http.path binds the whole path
+		p.Name = f.StructField.Name
+		p.Required = true
+	}
+	if in == "query" && s.Type == "array" {
+		explode := false
+		switch gbind.CollectionFormat(f.CollectionFormat) {
+		case gbind.CollectionCSV:
+			p.Style, p.Explode = "form", &explode
+		case gbind.CollectionSSV:
+			p.Style, p.Explode = "spaceDelimited", &explode
+		case gbind.CollectionPipes:
+			p.Style, p.Explode = "pipeDelimited", &explode
+		case gbind.CollectionBrackets:
+			p.Name += "[]"
+		}
+	}
+	return p
+}
+
+// fieldSchema the schema of the field with the default and the validate rules
+func fieldSchema(f compiled.Field, validateTag string) (*Schema, bool) {
+	t := schema.Deref(f.StructField.Type)
+	s := schema.Basic(t)
+	if t == durationType {
+		// bound by time.ParseDuration
+		s = &Schema{Type: "string", Format: "duration"}
+	}
+	if f.HasDefault {
+		s.Default = defaultValue(s, f)
+	}
+	s.Description = f.StructField.Tag.Get(DescTag)
+	required := schema.Apply(s, f.StructField.Tag.Get(validateTag))
+	return s, required
+}
+
+func defaultValue(s *Schema, f compiled.Field) interface{} {
+	if s.Type != "array" {
+		return s.Value(f.Default)
+	}
+	vs := strings.Split(f.Default, f.DefaultSplitFlag)
+	values := make([]interface{}, 0, len(vs))
+	for _, v := range vs {
+		values = append(values, s.Items.Value(v))
+	}
+	return values
+}
+
+func jsonSchema(g *gbind.Gbind, v interface{}) (*Schema, error) {
+	if g == nil {
+		return gbind.JSONSchema(v)
+	}
+	return g.JSONSchema(v)
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bdjimmy/gbind"
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	City string `json:"city" validate:"required" desc:"the city"`
+	Zip  string `json:"zip,omitempty" validate:"len=6"`
+}
+
+type Params struct {
+	API     string        `gbind:"http.path"`
+	Page    int           `gbind:"http.query.page,default=1" validate:"min=1" desc:"the page number"`
+	Size    *int          `gbind:"http.query.size,default=10" validate:"gt=0,lte=100"`
+	Sort    string        `gbind:"http.query.sort,default=asc" validate:"oneof=asc desc"`
+	Uids    []int         `gbind:"http.query.uids,collection=csv,default=1|2" validate:"max=10,dive,gte=1"`
+	Token   string        `gbind:"http.cookie.Token" validate:"required"`
+	Timeout time.Duration `gbind:"http.header.X-Timeout,default=1s"`
+	Tags    []string      `gbind:"http.form.tags,collection=brackets" validate:"required,dive,max=8"`
+	Email   string        `json:"email" validate:"required,email"`
+	Address *Address      `json:"address"`
+	Codes   map[string]int
+	Ignored string `json:"-"`
+}
+
+func TestGenerate(t *testing.T) {
+	op, err := Generate(nil, &Params{})
+	assert.Nil(t, err)
+	bs, err := json.MarshalIndent(op, "", "  ")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+  "parameters": [
+    {"name": "API", "in": "path", "required": true, "schema": {"type": "string"}},
+    {"name": "page", "in": "query", "description": "the page number",
+     "schema": {"type": "integer", "format": "int64", "default": 1, "minimum": 1}},
+    {"name": "size", "in": "query",
+     "schema": {"type": "integer", "format": "int64", "default": 10, "exclusiveMinimum": 0, "maximum": 100}},
+    {"name": "sort", "in": "query", "schema": {"type": "string", "default": "asc", "enum": ["asc", "desc"]}},
+    {"name": "uids", "in": "query", "style": "form", "explode": false,
+     "schema": {"type": "array", "default": [1, 2], "maxItems": 10, "items": {"type": "integer", "format": "int64", "minimum": 1}}},
+    {"name": "Token", "in": "cookie", "required": true, "schema": {"type": "string"}},
+    {"name": "X-Timeout", "in": "header", "schema": {"type": "string", "format": "duration", "default": "1s"}}
+  ],
+  "requestBody": {
+    "required": true,
+    "content": {
+      "application/json": {"schema": {
+        "type": "object",
+        "properties": {
+          "email": {"type": "string", "format": "email"},
+          "address": {"type": "object", "properties": {
+            "city": {"type": "string", "description": "the city"},
+            "zip": {"type": "string", "minLength": 6, "maxLength": 6}
+          }, "required": ["city"]},
+          "Codes": {"type": "object", "additionalProperties": {"type": "integer", "format": "int64"}}
+        },
+        "required": ["email"]
+      }},
+      "application/x-www-form-urlencoded": {"schema": {
+        "type": "object",
+        "properties": {"tags": {"type": "array", "items": {"type": "string", "maxLength": 8}}},
+        "required": ["tags"]
+      }},
+      "multipart/form-data": {"schema": {
+        "type": "object",
+        "properties": {"tags": {"type": "array", "items": {"type": "string", "maxLength": 8}}},
+        "required": ["tags"]
+      }}
+    }
+  }
+}`, string(bs))
+}
+
+func TestGenerateOptions(t *testing.T) {
+	type Foo struct {
+		Ids []int `bind:"http.query.ids,default=1-2" check:"required"`
+	}
+	g := gbind.NewGbind(gbind.WithBindTag("bind"), gbind.WithValidateTag("check"), gbind.WithDefaultSplitFlag("-"))
+	op, err := Generate(g, (*Foo)(nil))
+	assert.Nil(t, err)
+	assert.Nil(t, op.RequestBody)
+	assert.Equal(t, []*Parameter{{
+		Name:     "ids",
+		In:       "query",
+		Required: true,
+		Schema: &Schema{
+			Type:    "array",
+			Default: []interface{}{int64(1), int64(2)},
+			Items:   &Schema{Type: "integer", Format: "int64"},
+		},
+	}}, op.Parameters)
+
+	_, err = Generate(nil, Foo{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,80 @@
+package gbind
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// the registries of the functions used by the compiled structs
+const (
+	registryExecers      = "execer"
+	registryTransforms   = "transform"
+	registryDefaultFuncs = "default_func"
+	registrySanitizers   = "sanitizer"
+)
+
+// registry the functions registered by name, it is read without locking and copied on write,
+// so that the functions can be registered while binding
+type registry[T any] struct {
+	lock sync.Mutex
+	m    atomic.Pointer[map[string]T]
+}
+
+func newRegistry[T any](m map[string]T) *registry[T] {
+	r := &registry[T]{}
+	r.m.Store(&m)
+	return r
+}
+
+func (r *registry[T]) get(name string) (fn T, ok bool) {
+	fn, ok = (*r.m.Load())[name]
+	return
+}
+
+func (r *registry[T]) set(name string, fn T) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	m := *r.m.Load()
+	nm := make(map[string]T, len(m)+1)
+	for k, v := range m {
+		nm[k] = v
+	}
+	nm[name] = fn
+	r.m.Store(&nm)
+}
+
+// dependency a name of the registries referenced by the tags of a compiled struct,
+// the name may not be registered yet, e.g. the source of a plugin loaded later
+type dependency struct {
+	registry string
+	name     string
+}
+
+// depend records that the struct is compiled with the name of the registry
+func (sv *structType) depend(registry, name string) {
+	if sv.deps == nil {
+		sv.deps = map[dependency]bool{}
+	}
+	sv.deps[dependency{registry: registry, name: name}] = true
+}
+
+// current reports whether nothing has been registered since the struct was compiled
+func (sv *structType) current() bool {
+	return sv.version == sv.gbind.version.Load()
+}
+
+// registered bumps the version of the registries and removes the compiled structs referencing the name,
+// they are compiled again with the registered function by the next binding
+func (g *Gbind) registered(registry, name string) {
+	g.version.Add(1)
+	dep := dependency{registry: registry, name: name}
+	g.localCache.invalidate(func(st *structType) bool {
+		return st.deps[dep]
+	})
+}
+
+// RegistryVersion returns the version of the registries of g, which is increased by every
+// RegisterBindFunc, RegisterTransform, RegisterDefaultFunc and RegisterSanitizer
+func (g *Gbind) RegistryVersion() uint64 {
+	return g.version.Load()
+}
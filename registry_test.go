@@ -0,0 +1,110 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterBindFuncInvalidates(t *testing.T) {
+	type plugin struct {
+		Key string `gbind:"simple.key"`
+	}
+	type params struct {
+		Appkey string `gbind:"http.query.appkey"`
+	}
+	g := NewGbind()
+	ctx := context.WithValue(context.Background(), exprKey{}, "plugin-value")
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?appkey=abc", nil)
+
+	// the source is not registered yet
+	p := &plugin{}
+	_, err := g.Bind(ctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "", p.Key)
+	_, err = g.Bind(ctx, &params{}, req)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, g.CacheStats().Size)
+
+	g.RegisterBindFunc("simple", NewSimpleExecer)
+	assert.Equal(t, uint64(1), g.RegistryVersion())
+	assert.Equal(t, 1, g.CacheStats().Size)
+	_, err = g.Bind(ctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "plugin-value", p.Key)
+	assert.Equal(t, uint64(3), g.CacheStats().Compiles)
+
+	// params does not use the source
+	_, err = g.Bind(ctx, &params{}, req)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), g.CacheStats().Compiles)
+}
+
+func TestRegisterTransformInvalidates(t *testing.T) {
+	type params struct {
+		Name string `gbind:"http.query.name,shout" sanitize:"wrap"`
+	}
+	g := NewGbind()
+	g.RegisterSanitizer("wrap", func(value reflect.Value, param string) error {
+		return nil
+	})
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?name=abc", nil)
+	bind := func() string {
+		p := &params{}
+		_, err := g.Bind(context.Background(), p, req)
+		assert.Nil(t, err)
+		return p.Name
+	}
+	// the transform is not registered yet, the unknown option is ignored
+	assert.Equal(t, "abc", bind())
+
+	g.RegisterTransform("shout", func(v string) (string, error) {
+		return strings.ToUpper(v), nil
+	})
+	assert.Equal(t, "ABC", bind())
+
+	g.RegisterSanitizer("wrap", func(value reflect.Value, param string) error {
+		value.SetString("<" + value.String() + ">")
+		return nil
+	})
+	assert.Equal(t, "<ABC>", bind())
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	type params struct {
+		Key  string `gbind:"simple.key"`
+		Name string `gbind:"http.query.name" validate:"required,is-name"`
+	}
+	g := NewGbind()
+	assert.Nil(t, g.RegisterCustomValidation("is-name", func(fl validator.FieldLevel) bool { return true }))
+	ctx := context.WithValue(context.Background(), exprKey{}, "plugin-value")
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?name=abc", nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := g.BindWithValidate(ctx, &params{}, req)
+			assert.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			g.RegisterBindFunc("simple", NewSimpleExecer)
+			assert.Nil(t, g.RegisterCustomValidation("is-name", func(fl validator.FieldLevel) bool { return true }))
+		}()
+	}
+	wg.Wait()
+
+	p := &params{}
+	_, err := g.BindWithValidate(ctx, p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "plugin-value", p.Key)
+	assert.Equal(t, uint64(16), g.RegistryVersion())
+}
@@ -0,0 +1,143 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RequestMeta the read-only facts of the request being bound,
+// the query and form values are shared with the http execers
+type RequestMeta struct {
+	md *httpMetaData
+}
+
+// requestMeta returns the RequestMeta of ctx, the zero RequestMeta if the binding is not a http request
+func requestMeta(ctx context.Context) RequestMeta {
+	md, _ := ctx.Value(metaKey{}).(*httpMetaData)
+	if md != nil && md.request == nil {
+		md = nil
+	}
+	return RequestMeta{md: md}
+}
+
+// Request returns the request being bound, nil if the binding is not a http request
+func (m RequestMeta) Request() *http.Request {
+	if m.md == nil {
+		return nil
+	}
+	return m.md.request
+}
+
+// Method returns the method of the request
+func (m RequestMeta) Method() string {
+	if m.md == nil {
+		return ""
+	}
+	return m.md.request.Method
+}
+
+// Query returns the query values of key
+func (m RequestMeta) Query(key string) []string {
+	if m.md == nil {
+		return nil
+	}
+	return m.md.getQueryArray(key, CollectionMulti)
+}
+
+// Form returns the form values of key
+func (m RequestMeta) Form(key string) []string {
+	if m.md == nil {
+		return nil
+	}
+	vs, _ := m.md.getFormArray(key, CollectionMulti)
+	return vs
+}
+
+// Header returns the header values of key
+func (m RequestMeta) Header(key string) []string {
+	if m.md == nil {
+		return nil
+	}
+	return m.md.request.Header.Values(key)
+}
+
+// Cookie returns the unescaped cookie value of key
+func (m RequestMeta) Cookie(key string) (string, bool) {
+	if m.md == nil {
+		return "", false
+	}
+	c, err := m.md.request.Cookie(key)
+	if err != nil {
+		return "", false
+	}
+	v, _ := url.QueryUnescape(c.Value)
+	return v, true
+}
+
+// RequestValidationFunc a validation which receives the facts of the request being bound,
+// fl.Param() is the param of the rule in the validate tag
+type RequestValidationFunc func(meta RequestMeta, fl validator.FieldLevel) bool
+
+// wrapRequestValidation adapts fn to validator.FuncCtx, the RequestMeta is taken from the bind context
+func wrapRequestValidation(fn RequestValidationFunc) validator.FuncCtx {
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fn(requestMeta(ctx), fl)
+	}
+}
+
+// builtinRequestValidations the built-in request-aware validations
+//
+//	required_if_method=POST PUT    the field is required when the method is one of the params
+//	required_with_query=appkey     the field is required when the query appkey is present
+//	required_without_query=appkey  the field is required when the query appkey is absent
+//	required_with_header=X-Sign    the field is required when the header X-Sign is present
+//	required_without_header=X-Sign the field is required when the header X-Sign is absent
+var builtinRequestValidations = map[string]RequestValidationFunc{
+	"required_if_method": func(meta RequestMeta, fl validator.FieldLevel) bool {
+		for _, method := range strings.Fields(fl.Param()) {
+			if strings.EqualFold(method, meta.Method()) {
+				return hasValue(fl.Field())
+			}
+		}
+		return true
+	},
+	"required_with_query": func(meta RequestMeta, fl validator.FieldLevel) bool {
+		return len(meta.Query(fl.Param())) == 0 || hasValue(fl.Field())
+	},
+	"required_without_query": func(meta RequestMeta, fl validator.FieldLevel) bool {
+		return len(meta.Query(fl.Param())) > 0 || hasValue(fl.Field())
+	},
+	"required_with_header": func(meta RequestMeta, fl validator.FieldLevel) bool {
+		return len(meta.Header(fl.Param())) == 0 || hasValue(fl.Field())
+	},
+	"required_without_header": func(meta RequestMeta, fl validator.FieldLevel) bool {
+		return len(meta.Header(fl.Param())) > 0 || hasValue(fl.Field())
+	},
+}
+
+// registerRequestValidations registers the built-in request-aware validations
+func registerRequestValidations(v *validator.Validate) error {
+	for tag, fn := range builtinRequestValidations {
+		if err := v.RegisterValidationCtx(tag, wrapRequestValidation(fn), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasValue the same as the required validation of validator
+func hasValue(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return !field.IsNil()
+	case reflect.Invalid:
+		return false
+	default:
+		return !field.IsZero()
+	}
+}
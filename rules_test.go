@@ -0,0 +1,83 @@
+package gbind
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRequestValidations(t *testing.T) {
+	type Foo struct {
+		Sig    string `gbind:"http.query.sig" validate:"required_if_method=POST PUT" err_msg:"sig is required"`
+		APIKey string `gbind:"http.header.X-Api-Key" validate:"required_without_query=appkey" err_msg:"X-Api-Key is required"`
+		Nonce  string `gbind:"http.form.nonce" validate:"required_with_header=X-Sign" err_msg:"nonce is required"`
+	}
+	for testName, tt := range map[string]struct {
+		req    *http.Request
+		expect string
+	}{
+		"get": {
+			newReq().setMethod(http.MethodGet).addQueryParam("appkey", "a").r(), "",
+		},
+		"post-without-sig": {
+			newReq().setMethod(http.MethodPost).addQueryParam("appkey", "a").r(), "sig is required",
+		},
+		"post-with-sig": {
+			newReq().setMethod(http.MethodPost).addQueryParam("appkey", "a").addQueryParam("sig", "s").r(), "",
+		},
+		"without-appkey": {
+			newReq().r(), "X-Api-Key is required",
+		},
+		"without-appkey-with-api-key": {
+			newReq().addHeader("X-Api-Key", "k").r(), "",
+		},
+		"with-sign": {
+			newReq().addQueryParam("appkey", "a").addHeader("X-Sign", "s").r(), "nonce is required",
+		},
+		"with-sign-and-nonce": {
+			newReq().addQueryParam("appkey", "a").addHeader("X-Sign", "s").addFormParam("nonce", "n").r(), "",
+		},
+	} {
+		_, err := BindWithValidate(context.Background(), &Foo{}, tt.req)
+		if tt.expect == "" {
+			assert.Nil(t, err, testName)
+		} else {
+			assert.NotNil(t, err, testName)
+			assert.Equal(t, tt.expect, err.Error(), testName)
+		}
+	}
+}
+
+func TestRegisterRequestValidation(t *testing.T) {
+	g := NewGbind()
+	err := g.RegisterRequestValidation("required_with_cookie", func(meta RequestMeta, fl validator.FieldLevel) bool {
+		if _, ok := meta.Cookie(fl.Param()); !ok {
+			return true
+		}
+		return hasValue(fl.Field())
+	}, true)
+	assert.Nil(t, err)
+
+	type Foo struct {
+		UID int `gbind:"http.query.uid" validate:"required_with_cookie=Token"`
+	}
+	_, err = g.BindWithValidate(context.Background(), &Foo{}, newReq().r())
+	assert.Nil(t, err)
+
+	_, err = g.BindWithValidate(context.Background(), &Foo{}, newReq().addCookie("Token", "t").r())
+	assert.NotNil(t, err)
+
+	_, err = g.BindWithValidate(context.Background(), &Foo{}, newReq().addCookie("Token", "t").addQueryParam("uid", "1").r())
+	assert.Nil(t, err)
+
+	// not a http request
+	g.RegisterBindFunc("simple", NewSimpleExecer)
+	type Bar struct {
+		Key string `gbind:"simple.key" validate:"required_if_method=GET"`
+	}
+	_, err = g.BindWithValidate(context.WithValue(context.Background(), exprKey{}, ""), &Bar{}, nil)
+	assert.Nil(t, err)
+}
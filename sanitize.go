@@ -0,0 +1,260 @@
+package gbind
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SanitizeFunc sanitizes the value of the field in place, param is the param of the sanitizer in the tag,
+// e.g. `sanitize:"trim,clamp=1 100"`
+type SanitizeFunc func(value reflect.Value, param string) error
+
+// sanitizer one sanitizer of the sanitize tag
+type sanitizer struct {
+	name  string
+	param string
+	fn    SanitizeFunc
+}
+
+// newSanitizers returns the built-in sanitizers
+func newSanitizers() map[string]SanitizeFunc {
+	return map[string]SanitizeFunc{
+		"trim": StringSanitizer(func(s, _ string) (string, error) {
+			return strings.TrimSpace(s), nil
+		}),
+		"lower": StringSanitizer(func(s, _ string) (string, error) {
+			return strings.ToLower(s), nil
+		}),
+		"upper": StringSanitizer(func(s, _ string) (string, error) {
+			return strings.ToUpper(s), nil
+		}),
+		"strip_html": StringSanitizer(func(s, _ string) (string, error) {
+			return stripHTML(s), nil
+		}),
+		"collapse_space": StringSanitizer(func(s, _ string) (string, error) {
+			return strings.Join(strings.Fields(s), " "), nil
+		}),
+		"nfc": StringSanitizer(func(s, _ string) (string, error) {
+			return norm.NFC.String(s), nil
+		}),
+		"truncate": StringSanitizer(truncate),
+		"clamp":    clamp,
+	}
+}
+
+// StringSanitizer adapts fn to SanitizeFunc, which is applied to the string fields
+// and the elements of string slices and arrays, other kinds are left untouched
+func StringSanitizer(fn func(s, param string) (string, error)) SanitizeFunc {
+	var sanitize SanitizeFunc
+	sanitize = func(value reflect.Value, param string) error {
+		switch value.Kind() {
+		case reflect.String:
+			s, err := fn(value.String(), param)
+			if err != nil {
+				return err
+			}
+			value.SetString(s)
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				if err := sanitize(value.Index(i), param); err != nil {
+					return err
+				}
+			}
+		case reflect.Ptr:
+			if !value.IsNil() {
+				return sanitize(value.Elem(), param)
+			}
+		}
+		return nil
+	}
+	return sanitize
+}
+
+// stripHTML extracts the text of s by the html tokenizer, the tags, comments and the content of script and style
+// are dropped, including the unclosed tags like `<img src=x onerror=alert(1)`, the entities are decoded.
+// The result is plain text but not an XSS filter, `&lt;script&gt;` becomes `<script>`, escape it when writing html
+func stripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+	var (
+		b   strings.Builder
+		raw bool
+	)
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF, the unclosed tag at the end is dropped
+			return b.String()
+		case html.TextToken:
+			if !raw {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			raw = isRawText(name)
+		case html.EndTagToken:
+			raw = false
+		}
+	}
+}
+
+// isRawText reports whether the content of the tag is not text
+func isRawText(name []byte) bool {
+	switch string(name) {
+	case "script", "style":
+		return true
+	}
+	return false
+}
+
+// truncate truncates the string to at most param runes
+func truncate(s, param string) (string, error) {
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid truncate param %q", param)
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s, nil
+	}
+	return string([]rune(s)[:n]), nil
+}
+
+// clamp clamps the numbers into [min, max], `sanitize:"clamp=1 100"`
+func clamp(value reflect.Value, param string) error {
+	bounds := strings.Fields(param)
+	if len(bounds) != 2 {
+		return fmt.Errorf("invalid clamp param %q", param)
+	}
+	min, err := strconv.ParseFloat(bounds[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid clamp param %q", param)
+	}
+	max, err := strconv.ParseFloat(bounds[1], 64)
+	if err != nil || max < min {
+		return fmt.Errorf("invalid clamp param %q", param)
+	}
+	return clampValue(value, min, max)
+}
+
+func clampValue(value reflect.Value, min, max float64) error {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v := float64(value.Int()); v < min {
+			value.SetInt(int64(min))
+		} else if v > max {
+			value.SetInt(int64(max))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v := float64(value.Uint()); v < min {
+			value.SetUint(uint64(min))
+		} else if v > max {
+			value.SetUint(uint64(max))
+		}
+	case reflect.Float32, reflect.Float64:
+		if v := value.Float(); v < min {
+			value.SetFloat(min)
+		} else if v > max {
+			value.SetFloat(max)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := clampValue(value.Index(i), min, max); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return clampValue(value.Elem(), min, max)
+		}
+	}
+	return nil
+}
+
+// parseSanitizeTag parses `sanitize:"trim,clamp=1 100"`
+func (sv *structType) parseSanitizeTag(tag string, ns string) ([]sanitizer, error) {
+	var sanitizers []sanitizer
+	for _, part := range strings.Split(tag, ",") {
+		name, param := head(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		sv.depend(registrySanitizers, name)
+		fn, ok := sv.gbind.sanitizers.get(name)
+		if !ok {
+			return nil, e("unknown sanitizer %q of %s", name, ns)
+		}
+		sanitizers = append(sanitizers, sanitizer{name: name, param: param, fn: fn})
+	}
+	return sanitizers, nil
+}
+
+// sanitize runs the sanitizers of the fields, the nil pointers are left nil
+func (sv *structType) sanitize(rv reflect.Value) error {
+	return sv.eachRoot(rv, func(root reflect.Value) error {
+		for _, f := range sv.sanitizeFields {
+			value, ok := lookupField(root, f.index)
+			if !ok {
+				continue
+			}
+			for _, s := range f.sanitizers {
+				if err := s.fn(value, s.param); err != nil {
+					return e("sanitize %s of %s: %v", s.name, f.namespace, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// eachRoot calls fn with rv, or each element of rv if the binding is a slice, array or map of the struct
+func (sv *structType) eachRoot(rv reflect.Value, fn func(root reflect.Value) error) error {
+	if !sv.elem {
+		return fn(rv)
+	}
+	c := rv.Elem()
+	if c.Kind() != reflect.Map {
+		for i := 0; i < c.Len(); i++ {
+			if err := fn(c.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the values of the map are not addressable
+	iter := c.MapRange()
+	for iter.Next() {
+		v := reflect.New(iter.Value().Type()).Elem()
+		v.Set(iter.Value())
+		if err := fn(v); err != nil {
+			return err
+		}
+		c.SetMapIndex(iter.Key(), v)
+	}
+	return nil
+}
+
+// lookupField the same as fieldByIndexs, but it never allocates the nil pointers
+func lookupField(v reflect.Value, indexs []int) (reflect.Value, bool) {
+	for _, i := range indexs {
+		v = reflect.Indirect(v)
+		if !v.IsValid() {
+			return v, false
+		}
+		v = v.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,123 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizers(t *testing.T) {
+	sanitizers := newSanitizers()
+	for testName, tt := range map[string]struct {
+		value  interface{}
+		name   string
+		param  string
+		expect interface{}
+	}{
+		"trim":                {struct{ F string }{" a "}, "trim", "", "a"},
+		"strip_html":          {struct{ F string }{"<b>a</b><script>x</script><style>y</style>"}, "strip_html", "", "a"},
+		"strip_html-unclosed": {struct{ F string }{"a<img src=x onerror=alert(1)"}, "strip_html", "", "a"},
+		"strip_html-entities": {struct{ F string }{"a &amp; b &lt;i&gt; <!-- c -->"}, "strip_html", "", "a & b <i> "},
+		"strip_html-text":     {struct{ F string }{"a > b"}, "strip_html", "", "a > b"},
+		"collapse_space":      {struct{ F string }{" a \t b\n c "}, "collapse_space", "", "a b c"},
+		"nfc":                 {struct{ F string }{"e\u0301"}, "nfc", "", "\u00e9"},
+		"truncate":            {struct{ F string }{"你好世界"}, "truncate", "2", "你好"},
+		"lower-slice":         {struct{ F []string }{[]string{"A", "B"}}, "lower", "", []string{"a", "b"}},
+		"clamp-int-max":       {struct{ F int }{101}, "clamp", "1 100", 100},
+		"clamp-int-min":       {struct{ F int }{-1}, "clamp", "1 100", 1},
+		"clamp-uint":          {struct{ F uint8 }{0}, "clamp", "1 100", uint8(1)},
+		"clamp-float":         {struct{ F float64 }{0.5}, "clamp", "1 100", float64(1)},
+		"clamp-slice":         {struct{ F []int }{[]int{0, 50, 200}}, "clamp", "1 100", []int{1, 50, 100}},
+		"clamp-string-ignore": {struct{ F string }{"abc"}, "clamp", "1 100", "abc"},
+	} {
+		val := reflect.New(reflect.TypeOf(tt.value)).Elem()
+		val.Set(reflect.ValueOf(tt.value))
+		f := val.Field(0)
+		err := sanitizers[tt.name](f, tt.param)
+		assert.Nil(t, err, testName)
+		assert.Equal(t, tt.expect, f.Interface(), testName)
+	}
+
+	var v int
+	assert.NotNil(t, sanitizers["clamp"](reflect.ValueOf(&v).Elem(), "100 1"))
+	assert.NotNil(t, sanitizers["clamp"](reflect.ValueOf(&v).Elem(), "1"))
+	var s string
+	assert.NotNil(t, sanitizers["truncate"](reflect.ValueOf(&s).Elem(), "x"))
+}
+
+func TestSanitize(t *testing.T) {
+	type Foo struct {
+		Name    string  `gbind:"http.query.name" sanitize:"strip_html,collapse_space" validate:"max=5"`
+		Size    int     `gbind:"http.query.size,default=10" sanitize:"clamp=1 50"`
+		Comment *string `json:"comment" sanitize:"trim"`
+	}
+
+	// sanitize before validate
+	{
+		f := &Foo{}
+		req := newReq().addQueryParam("name", " <b>a</b>   b ").addQueryParam("size", "100").setBody(`{}`).r()
+		_, err := BindWithValidate(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "a b", f.Name)
+		assert.Equal(t, 50, f.Size)
+		assert.Nil(t, f.Comment)
+	}
+
+	// Bind sanitizes too
+	{
+		f := &Foo{}
+		req := newReq().setBody(`{"comment":"  hi  "}`).r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "hi", *f.Comment)
+	}
+
+	// top-level slice
+	{
+		type Item struct {
+			Name string `json:"name" sanitize:"trim,upper"`
+		}
+		items := []Item{}
+		req := newReq().setBody(`[{"name":" a "},{"name":"b "}]`).r()
+		_, err := Bind(context.Background(), &items, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []Item{{"A"}, {"B"}}, items)
+
+		m := map[string]Item{}
+		req = newReq().setBody(`{"x":{"name":" a "}}`).r()
+		_, err = Bind(context.Background(), &m, req)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]Item{"x": {"A"}}, m)
+	}
+}
+
+func TestRegisterSanitizer(t *testing.T) {
+	g := NewGbind(WithSanitizeTag("clean"))
+	g.RegisterSanitizer("mask", StringSanitizer(func(s, param string) (string, error) {
+		if len(s) < 4 {
+			return "", errors.New("too short")
+		}
+		return s[:len(s)-4] + param, nil
+	}))
+
+	type Foo struct {
+		Phone string `gbind:"http.query.phone" clean:"mask=****"`
+	}
+	f := &Foo{}
+	_, err := g.Bind(context.Background(), f, newReq().addQueryParam("phone", "13800001234").r())
+	assert.Nil(t, err)
+	assert.Equal(t, "1380000****", f.Phone)
+
+	_, err = g.Bind(context.Background(), &Foo{}, newReq().addQueryParam("phone", "1").r())
+	assert.NotNil(t, err)
+
+	// unknown sanitizer
+	type Bar struct {
+		Phone string `gbind:"http.query.phone" clean:"unknown"`
+	}
+	_, err = g.Bind(context.Background(), &Bar{}, newReq().r())
+	assert.NotNil(t, err)
+}
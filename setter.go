@@ -0,0 +1,140 @@
+package gbind
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setter sets the values to a field, it is selected by the type of the field when compiled,
+// so that the binding does not switch on the type of every value
+type setter func(value reflect.Value, vs []string) error
+
+// elemSetter sets a value to a field or an element of the slices and arrays
+type elemSetter func(value reflect.Value, val string) error
+
+// newSetter selects the setter of the type t, the values of the unsupported types are ignored
+func newSetter(t reflect.Type) setter {
+	if t == durationType {
+		return setDuration
+	}
+	if isTextUnmarshaler(t) {
+		return newScalarSetter(setText)
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return newSliceSetter(newElemSetter(t.Elem()))
+	case reflect.Array:
+		return newArraySetter(newElemSetter(t.Elem()))
+	}
+	return newScalarSetter(newElemSetter(t))
+}
+
+// newElemSetter selects the setter of a single value of the type t
+func newElemSetter(t reflect.Type) elemSetter {
+	if isTextUnmarshaler(t) {
+		return setText
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return setBool
+	case reflect.Float32:
+		return setFloat32
+	case reflect.Float64:
+		return setFloat64
+	case reflect.Int:
+		return setInt
+	case reflect.Int8:
+		return setInt8
+	case reflect.Int16:
+		return setInt16
+	case reflect.Int32:
+		return setInt32
+	case reflect.Int64:
+		return setInt64
+	case reflect.Uint:
+		return setUint
+	case reflect.Uint8:
+		return setUint8
+	case reflect.Uint16:
+		return setUint16
+	case reflect.Uint32:
+		return setUint32
+	case reflect.Uint64:
+		return setUint64
+	case reflect.String:
+		return setString
+	}
+	return setNothing
+}
+
+func newScalarSetter(set elemSetter) setter {
+	return func(value reflect.Value, vs []string) error {
+		return set(value, vs[0])
+	}
+}
+
+func newSliceSetter(set elemSetter) setter {
+	return func(value reflect.Value, vs []string) error {
+		slice := reflect.MakeSlice(value.Type(), len(vs), len(vs))
+		for i, val := range vs {
+			if err := set(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+}
+
+func newArraySetter(set elemSetter) setter {
+	return func(value reflect.Value, vs []string) error {
+		if len(vs) != value.Len() {
+			return fmt.Errorf("%q is not valid value for %s", vs, value.Type().String())
+		}
+		for i, val := range vs {
+			if err := set(value.Index(i), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// isTextUnmarshaler reports whether the pointer to t implements encoding.TextUnmarshaler, e.g. time.Time
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func setDuration(value reflect.Value, vs []string) error {
+	return setTimeDuration(vs, value)
+}
+
+func setText(value reflect.Value, val string) error {
+	return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+}
+
+func setBool(value reflect.Value, val string) error    { return setBoolField(val, value) }
+func setFloat32(value reflect.Value, val string) error { return setFloatField(val, 32, value) }
+func setFloat64(value reflect.Value, val string) error { return setFloatField(val, 64, value) }
+func setInt(value reflect.Value, val string) error     { return setIntField(val, 0, value) }
+func setInt8(value reflect.Value, val string) error    { return setIntField(val, 8, value) }
+func setInt16(value reflect.Value, val string) error   { return setIntField(val, 16, value) }
+func setInt32(value reflect.Value, val string) error   { return setIntField(val, 32, value) }
+func setInt64(value reflect.Value, val string) error   { return setIntField(val, 64, value) }
+func setUint(value reflect.Value, val string) error    { return setUintField(val, 0, value) }
+func setUint8(value reflect.Value, val string) error   { return setUintField(val, 8, value) }
+func setUint16(value reflect.Value, val string) error  { return setUintField(val, 16, value) }
+func setUint32(value reflect.Value, val string) error  { return setUintField(val, 32, value) }
+func setUint64(value reflect.Value, val string) error  { return setUintField(val, 64, value) }
+
+func setString(value reflect.Value, val string) error {
+	value.SetString(val)
+	return nil
+}
+
+func setNothing(value reflect.Value, val string) error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package gbind
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// level implements encoding.TextUnmarshaler by a named string
+type level string
+
+func (l *level) UnmarshalText(text []byte) error {
+	*l = level(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestSetter(t *testing.T) {
+	type params struct {
+		Since   time.Time      `gbind:"http.query.since"`
+		Until   *time.Time     `gbind:"http.query.until"`
+		IP      net.IP         `gbind:"http.query.ip"`
+		IPs     []net.IP       `gbind:"http.query.ips,collection=csv"`
+		Level   level          `gbind:"http.query.level"`
+		Levels  [2]level       `gbind:"http.query.levels"`
+		Uids    []uint16       `gbind:"http.query.uids"`
+		Timeout time.Duration  `gbind:"http.query.timeout"`
+		Ignored map[string]int `gbind:"http.query.ignored"`
+	}
+	req, _ := http.NewRequest("GET", "http://localhost:8080/?since=2024-01-02T03:04:05Z&until=2024-02-01T00:00:00Z"+
+		"&ip=127.0.0.1&ips=10.0.0.1,10.0.0.2&level=debug&levels=info&levels=warn&uids=1&uids=2&timeout=3s&ignored=1", nil)
+	p := &params{}
+	_, err := Bind(context.Background(), p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), p.Since)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *p.Until)
+	assert.Equal(t, net.ParseIP("127.0.0.1"), p.IP)
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")}, p.IPs)
+	assert.Equal(t, level("DEBUG"), p.Level)
+	assert.Equal(t, [2]level{"INFO", "WARN"}, p.Levels)
+	assert.Equal(t, []uint16{1, 2}, p.Uids)
+	assert.Equal(t, 3*time.Second, p.Timeout)
+	assert.Nil(t, p.Ignored)
+
+	req, _ = http.NewRequest("GET", "http://localhost:8080/?since=yesterday", nil)
+	_, err = Bind(context.Background(), &params{}, req)
+	assert.NotNil(t, err)
+	req, _ = http.NewRequest("GET", "http://localhost:8080/?uids=70000", nil)
+	_, err = Bind(context.Background(), &params{}, req)
+	assert.NotNil(t, err)
+	req, _ = http.NewRequest("GET", "http://localhost:8080/?levels=info", nil)
+	_, err = Bind(context.Background(), &params{}, req)
+	assert.NotNil(t, err)
+}
+
+func TestSetterCompiled(t *testing.T) {
+	type params struct {
+		Uids []int64 `gbind:"http.query.uids"`
+	}
+	st, err := defaultGbind.compile(&params{})
+	assert.Nil(t, err)
+	opt := &st.fields["params.Uids"].defaultOpt
+	assert.NotNil(t, opt.setter)
+	assert.Equal(t, reflect.TypeOf([]int64{}), opt.setterType)
+
+	var uids []int64
+	assert.Nil(t, opt.Set(context.Background(), reflect.ValueOf(&uids).Elem(), []string{"1", "2"}))
+	assert.Equal(t, []int64{1, 2}, uids)
+
+	// the values of the other types are set by the setter of their types
+	var s []string
+	assert.Nil(t, opt.Set(context.Background(), reflect.ValueOf(&s).Elem(), []string{"a"}))
+	assert.Equal(t, []string{"a"}, s)
+
+	var l level
+	assert.Nil(t, TrySet(reflect.ValueOf(&l).Elem(), []string{"error"}, nil))
+	assert.Equal(t, level("ERROR"), l)
+}
+
+type headerSetExecer struct {
+	key string
+}
+
+func (h *headerSetExecer) Exec(ctx context.Context, value reflect.Value, data interface{}, opt *DefaultOption) (context.Context, error) {
+	req := data.(*http.Request)
+	return ctx, opt.Set(ctx, value, strings.Split(req.Header.Get(h.key), ","))
+}
+
+func (h *headerSetExecer) Name() string {
+	return "csvheader"
+}
+
+func TestSetterCustomExecer(t *testing.T) {
+	type params struct {
+		Uids []int `gbind:"csvheader.X-Uids"`
+	}
+	g := NewGbind()
+	g.RegisterBindFunc("csvheader", func(values [][]byte) (Execer, error) {
+		return &headerSetExecer{key: string(values[1])}, nil
+	})
+	req, _ := http.NewRequest("GET", "http://localhost:8080/", nil)
+	req.Header.Set("X-Uids", "1,2,3")
+	p := &params{}
+	_, err := g.Bind(context.Background(), p, req)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, p.Uids)
+}
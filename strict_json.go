@@ -0,0 +1,190 @@
+package gbind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithDisallowUnknownFields causes the json binding to return a *JSONError
+// when the body contains keys which do not match any non-ignored, exported fields
+func WithDisallowUnknownFields(disallow bool) OptApply {
+	return func(opt *options) {
+		opt.disallowUnknownFields = disallow
+	}
+}
+
+// WithDisallowTrailingData causes the json binding to return a *JSONError
+// when the body contains data after the first json value, e.g. {"a":1}{"b":2}
+func WithDisallowTrailingData(disallow bool) OptApply {
+	return func(opt *options) {
+		opt.disallowTrailingData = disallow
+	}
+}
+
+// JSONError the json body is rejected by the strict json mode
+type JSONError struct {
+	// Path the json path of the offending value, e.g. items[2].qty, empty for the trailing data
+	Path string
+	// Offset the byte offset of the body where the error occurred
+	Offset int64
+	// Err the underlying error
+	Err error
+}
+
+func (err *JSONError) Error() string {
+	if err.Path == "" {
+		return fmt.Sprintf("gbind: json: %v", err.Err)
+	}
+	return fmt.Sprintf("gbind: json %s: %v", err.Path, err.Err)
+}
+
+// Unwrap returns the underlying error
+func (err *JSONError) Unwrap() error {
+	return err.Err
+}
+
+var errTrailingData = errors.New("invalid trailing data after the top-level value")
+
+const unknownFieldPrefix = `json: unknown field "`
+
+// strictDecode decodes the body into obj, the unknown fields and the trailing data are rejected
+// according to the options, body is read into memory to locate the unknown field
+func (sv *structType) strictDecode(body io.Reader, obj interface{}) error {
+	bs, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	if sv.gbind.options.useNumberForJSON {
+		decoder.UseNumber()
+	}
+	if sv.gbind.options.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(obj); err != nil {
+		if name := strings.TrimPrefix(err.Error(), unknownFieldPrefix); name != err.Error() {
+			name = strings.TrimSuffix(name, `"`)
+			var v interface{}
+			json.Unmarshal(bs, &v)
+			path, _ := unknownFieldPath(v, reflect.TypeOf(obj), "", name)
+			return &JSONError{Path: path, Offset: decoder.InputOffset(), Err: err}
+		}
+		return err
+	}
+	if sv.gbind.options.disallowTrailingData {
+		offset := decoder.InputOffset()
+		if _, err := decoder.Token(); err != io.EOF {
+			return &JSONError{Offset: offset, Err: errTrailingData}
+		}
+	}
+	return nil
+}
+
+// unknownFieldPath finds the path of the key name which is unknown to the struct it belongs to
+func unknownFieldPath(v interface{}, rt reflect.Type, path string, name string) (string, bool) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if reflect.PtrTo(rt).Implements(jsonUnmarshaler) {
+		return "", false
+	}
+	switch rt.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		fields := jsonFields(rt)
+		for _, k := range sortedKeys(m) {
+			ft, ok := fields[strings.ToLower(k)]
+			if !ok {
+				if k == name {
+					return joinPath(path, k), true
+				}
+				continue
+			}
+			if p, ok := unknownFieldPath(m[k], ft, joinPath(path, k), name); ok {
+				return p, true
+			}
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		for _, k := range sortedKeys(m) {
+			if p, ok := unknownFieldPath(m[k], rt.Elem(), joinPath(path, k), name); ok {
+				return p, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		vs, ok := v.([]interface{})
+		if !ok {
+			return "", false
+		}
+		for i, elem := range vs {
+			if p, ok := unknownFieldPath(elem, rt.Elem(), path+"["+strconv.Itoa(i)+"]", name); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonFields returns the json keys of the struct in lower case, the embedded structs are promoted
+func jsonFields(rt reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _ := head(tag, ",")
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, t := range jsonFields(ft) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = t
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,94 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownFieldPath(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Item struct {
+		Base
+		Qty  int `json:"qty"`
+		Skip int `json:"-"`
+	}
+	type Req struct {
+		Items []Item          `json:"items"`
+		Attrs map[string]Item `json:"attrs"`
+		Name  string
+	}
+	for testName, tt := range map[string]struct {
+		body   interface{}
+		name   string
+		expect string
+	}{
+		"top": {
+			map[string]interface{}{"name": "a", "foo": 1}, "foo", "foo",
+		},
+		"slice": {
+			map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"id": 1, "qty": 1},
+				map[string]interface{}{"ID": 1, "qyt": 1},
+			}}, "qyt", "items[1].qyt",
+		},
+		"map": {
+			map[string]interface{}{"attrs": map[string]interface{}{
+				"x": map[string]interface{}{"Skip": 1},
+			}}, "Skip", "attrs.x.Skip",
+		},
+	} {
+		path, ok := unknownFieldPath(tt.body, reflect.TypeOf(&Req{}), "", tt.name)
+		assert.True(t, ok, testName)
+		assert.Equal(t, tt.expect, path, testName)
+	}
+}
+
+func TestStrictJSON(t *testing.T) {
+	type Item struct {
+		Qty int `json:"qty"`
+	}
+	type Foo struct {
+		Name  string `json:"name"`
+		Items []Item `json:"items"`
+	}
+
+	// unknown fields
+	{
+		g := NewGbind(WithDisallowUnknownFields(true))
+		f := &Foo{}
+		_, err := g.Bind(context.Background(), f, newReq().setBody(`{"name":"a","items":[{"qty":1}]}`).r())
+		assert.Nil(t, err)
+		assert.Equal(t, &Foo{Name: "a", Items: []Item{{Qty: 1}}}, f)
+
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().setBody(`{"name":"a","items":[{"qty":1},{"qyt":2}]}`).r())
+		var jsonErr *JSONError
+		assert.True(t, errors.As(err, &jsonErr))
+		assert.Equal(t, "items[1].qyt", jsonErr.Path)
+
+		// trailing data is allowed
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().setBody(`{"name":"a"}{"name":"b"}`).r())
+		assert.Nil(t, err)
+	}
+
+	// trailing data
+	{
+		g := NewGbind(WithDisallowTrailingData(true))
+		_, err := g.Bind(context.Background(), &Foo{}, newReq().setBody(`{"name":"a"}  `).r())
+		assert.Nil(t, err)
+
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().setBody(`{"name":"a"}{"b":2}`).r())
+		var jsonErr *JSONError
+		assert.True(t, errors.As(err, &jsonErr))
+		assert.Equal(t, int64(12), jsonErr.Offset)
+
+		// unknown fields are allowed
+		_, err = g.Bind(context.Background(), &Foo{}, newReq().setBody(`{"foo":"a"}`).r())
+		assert.Nil(t, err)
+	}
+}
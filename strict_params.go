@@ -0,0 +1,85 @@
+package gbind
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithDisallowUnknownParams causes the binding to return an *UnknownParamsError when the request
+// carries query keys which no http.query field binds, the form keys are checked in the same way
+// if the struct has any http.form field
+func WithDisallowUnknownParams(disallow bool) OptApply {
+	return func(opt *options) {
+		opt.disallowUnknownParams = disallow
+	}
+}
+
+// UnknownParamsError the request carries the params which no field binds
+type UnknownParamsError struct {
+	// Source "query" or "form"
+	Source string
+	// Params the unknown keys, sorted
+	Params []string
+}
+
+func (err *UnknownParamsError) Error() string {
+	return fmt.Sprintf("gbind: unknown %s params: %s", err.Source, strings.Join(err.Params, ", "))
+}
+
+// knownParams the params bound by the http.query or http.form fields, key => collection format
+type knownParams map[string]CollectionFormat
+
+// add records the param bound with the format
+func (kp knownParams) add(param string, format CollectionFormat) {
+	kp[param] = format
+}
+
+// has reports whether the key of the request is bound by any field
+func (kp knownParams) has(key string) bool {
+	if _, ok := kp[key]; ok {
+		return true
+	}
+	param, index := head(key, "[")
+	if param == key || !strings.HasSuffix(index, "]") {
+		return false
+	}
+	switch kp[param] {
+	case CollectionBrackets:
+		return index == "]"
+	case CollectionIndexed:
+		i, err := strconv.Atoi(index[:len(index)-1])
+		return err == nil && i >= 0
+	}
+	return false
+}
+
+// unknown returns the sorted keys of values which are not bound
+func (kp knownParams) unknown(values url.Values) []string {
+	var params []string
+	for k := range values {
+		if !kp.has(k) {
+			params = append(params, k)
+		}
+	}
+	sort.Strings(params)
+	return params
+}
+
+// checkParams checks the query and form keys of the request in md
+func (sv *structType) checkParams(md *httpMetaData) error {
+	md.initQueryCache()
+	if params := sv.queryParams.unknown(md.queryCache); len(params) > 0 {
+		return &UnknownParamsError{Source: "query", Params: params}
+	}
+	if len(sv.formParams) == 0 {
+		return nil
+	}
+	md.initFormCache()
+	if params := sv.formParams.unknown(md.formCache); len(params) > 0 {
+		return &UnknownParamsError{Source: "form", Params: params}
+	}
+	return nil
+}
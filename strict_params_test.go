@@ -0,0 +1,66 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKnownParams(t *testing.T) {
+	kp := knownParams{}
+	kp.add("id", CollectionMulti)
+	kp.add("tags", CollectionBrackets)
+	kp.add("uids", CollectionIndexed)
+	for key, expect := range map[string]bool{
+		"id":      true,
+		"id[]":    false,
+		"tags":    true,
+		"tags[]":  true,
+		"tags[0]": false,
+		"uids[0]": true,
+		"uids[x]": false,
+		"uids[]":  false,
+		"other":   false,
+	} {
+		assert.Equal(t, expect, kp.has(key), key)
+	}
+}
+
+func TestDisallowUnknownParams(t *testing.T) {
+	type Foo struct {
+		Appkey string   `gbind:"http.query.appkey"`
+		Tags   []string `gbind:"http.query.tags,collection=brackets"`
+		Page   int      `gbind:"http.form.page"`
+	}
+	g := NewGbind(WithDisallowUnknownParams(true))
+
+	req := newReq().addQueryParam("appkey", "a").addQueryParam("tags[]", "x").addFormParam("page", "1").r()
+	_, err := g.Bind(context.Background(), &Foo{}, req)
+	assert.Nil(t, err)
+
+	req = newReq().addQueryParam("appkey", "a").addQueryParam("appkye", "a").addQueryParam("b", "a").r()
+	_, err = g.Bind(context.Background(), &Foo{}, req)
+	var paramsErr *UnknownParamsError
+	assert.True(t, errors.As(err, &paramsErr))
+	assert.Equal(t, "query", paramsErr.Source)
+	assert.Equal(t, []string{"appkye", "b"}, paramsErr.Params)
+
+	req = newReq().addFormParam("size", "1").r()
+	_, err = g.Bind(context.Background(), &Foo{}, req)
+	assert.True(t, errors.As(err, &paramsErr))
+	assert.Equal(t, "form", paramsErr.Source)
+	assert.Equal(t, []string{"size"}, paramsErr.Params)
+
+	// the form is not checked without http.form fields
+	type Bar struct {
+		Appkey string `gbind:"http.query.appkey"`
+	}
+	_, err = g.Bind(context.Background(), &Bar{}, newReq().addFormParam("size", "1").r())
+	assert.Nil(t, err)
+
+	// disabled by default
+	_, err = Bind(context.Background(), &Bar{}, newReq().addQueryParam("b", "a").r())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,65 @@
+package gbind
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithDisallowUnknownOptions causes the compiling of a struct to fail when an option of the bind tag
+// is neither a built-in option nor a registered transform, e.g. the typo `tirm`.
+// The unknown options are ignored by default, so the tags like `default=a,b` keep the default a
+func WithDisallowUnknownOptions(disallow bool) OptApply {
+	return func(opt *options) {
+		opt.disallowUnknownOptions = disallow
+	}
+}
+
+// TransformFunc transforms a value extracted from the source before it is set
+type TransformFunc func(string) (string, error)
+
+// newTransforms returns the built-in transforms
+func newTransforms() map[string]TransformFunc {
+	return map[string]TransformFunc{
+		"trim": func(s string) (string, error) {
+			return strings.TrimSpace(s), nil
+		},
+		"lower": func(s string) (string, error) {
+			return strings.ToLower(s), nil
+		},
+		"upper": func(s string) (string, error) {
+			return strings.ToUpper(s), nil
+		},
+	}
+}
+
+// transformer one step of the transform pipeline,
+// it splits the values when split is not empty, otherwise calls fn for each value
+type transformer struct {
+	name  string
+	split string
+	fn    TransformFunc
+}
+
+// transform applies the pipeline to vs, vs itself is never modified
+func transform(vs []string, pipeline []transformer) ([]string, error) {
+	for _, t := range pipeline {
+		if t.split != "" {
+			nvs := make([]string, 0, len(vs))
+			for _, v := range vs {
+				nvs = append(nvs, strings.Split(v, t.split)...)
+			}
+			vs = nvs
+			continue
+		}
+		nvs := make([]string, len(vs))
+		for i, v := range vs {
+			nv, err := t.fn(v)
+			if err != nil {
+				return nil, fmt.Errorf("transform %s: %w", t.name, err)
+			}
+			nvs[i] = nv
+		}
+		vs = nvs
+	}
+	return vs, nil
+}
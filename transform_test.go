@@ -0,0 +1,175 @@
+package gbind
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bdjimmy/gbind/internal/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	// the names checked by gbindvet
+	names := make([]string, 0, len(tags.Transforms))
+	for name := range newTransforms() {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, tags.Transforms, names)
+}
+
+func TestTransform(t *testing.T) {
+	// split and trim
+	{
+		type Foo struct {
+			Ids []int `gbind:"http.query.ids,split=,,trim"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("ids", "1, 2 ,3").r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 3}, f.Ids)
+	}
+
+	// split by the comma at the end of the tag
+	{
+		type Foo struct {
+			IDs []int `gbind:"http.query.ids,split=,"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("ids", "1,2,3").r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 3}, f.IDs)
+	}
+
+	// trim and lower
+	{
+		type Foo struct {
+			Email string `gbind:"http.query.email,trim,lower"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("email", " Foo@Bar.COM ").r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "foo@bar.com", f.Email)
+	}
+
+	// the default value is not transformed
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name,upper,default=abc"`
+		}
+		f := &Foo{}
+		req := newReq().r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", f.Name)
+	}
+
+	// the query cache is not modified
+	{
+		type Foo struct {
+			A string `gbind:"http.query.name,upper"`
+			B string `gbind:"http.query.name"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("name", "abc").r()
+		_, err := Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "ABC", f.A)
+		assert.Equal(t, "abc", f.B)
+	}
+}
+
+func TestUnknownOptions(t *testing.T) {
+	// the options of the previous releases are ignored unless they are transforms
+	type Foo struct {
+		Name  string   `gbind:"http.query.name,default=a,b"`
+		Typo  string   `gbind:"http.query.typo,tirm"`
+		Names []string `gbind:"http.query.names,default=a|b,c"`
+	}
+	f := &Foo{}
+	_, err := Bind(context.Background(), f, newReq().addQueryParam("typo", " x ").r())
+	assert.Nil(t, err)
+	assert.Equal(t, "a", f.Name)
+	assert.Equal(t, " x ", f.Typo)
+	assert.Equal(t, []string{"a", "b"}, f.Names)
+
+	g := NewGbind(WithDisallowUnknownOptions(true))
+	_, err = g.Bind(context.Background(), &Foo{}, newReq().r())
+	assert.EqualError(t, err, `gbind: unknown transform "b" of Foo.Name`)
+
+	// registered later, the struct is compiled again with the transform
+	g = NewGbind()
+	req := newReq().addQueryParam("typo", " x ").r()
+	_, err = g.Bind(context.Background(), &Foo{}, req)
+	assert.Nil(t, err)
+	g.RegisterTransform("tirm", func(s string) (string, error) {
+		return strings.TrimSpace(s), nil
+	})
+	f = &Foo{}
+	_, err = g.Bind(context.Background(), f, req)
+	assert.Nil(t, err)
+	assert.Equal(t, "x", f.Typo)
+}
+
+func TestTransformErrors(t *testing.T) {
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name,tirm"`
+		}
+		_, err := NewGbind(WithDisallowUnknownOptions(true)).Bind(context.Background(), &Foo{}, newReq().r())
+		if assert.NotNil(t, err) {
+			assert.Equal(t, `gbind: unknown transform "tirm" of Foo.Name`, err.Error())
+		}
+	}
+	{
+		type Foo struct {
+			Ids []int `gbind:"http.query.ids,split=,trim"`
+		}
+		_, err := Bind(context.Background(), &Foo{}, newReq().r())
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "gbind: empty split of Foo.Ids", err.Error())
+		}
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	g := NewGbind()
+	g.RegisterTransform("reverse", func(s string) (string, error) {
+		rs := []rune(s)
+		for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+			rs[i], rs[j] = rs[j], rs[i]
+		}
+		return string(rs), nil
+	})
+	g.RegisterTransform("nonempty", func(s string) (string, error) {
+		if strings.TrimSpace(s) == "" {
+			return "", errors.New("empty value")
+		}
+		return s, nil
+	})
+
+	{
+		type Foo struct {
+			Name string `gbind:"http.query.name,reverse"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("name", "abc").r()
+		_, err := g.Bind(context.Background(), f, req)
+		assert.Nil(t, err)
+		assert.Equal(t, "cba", f.Name)
+	}
+
+	{
+		type Foo struct {
+			Names []string `gbind:"http.query.names,split=|,nonempty"`
+		}
+		f := &Foo{}
+		req := newReq().addQueryParam("names", "a||b").r()
+		_, err := g.Bind(context.Background(), f, req)
+		assert.NotNil(t, err)
+	}
+}
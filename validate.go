@@ -1,8 +1,11 @@
 package gbind
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -14,38 +17,104 @@ type StructValidator interface {
 	ValidateStruct(interface{}) error
 }
 
-// Validator Validator
-var Validator StructValidator = &defaultValidator{}
+// ContextStructValidator a StructValidator receiving the context.Context of the binding,
+// Gbind prefers ValidateStructCtx when the validator implements it
+type ContextStructValidator interface {
+	StructValidator
+	ValidateStructCtx(context.Context, interface{}) error
+}
+
+// Validator the validator of the package level functions such as BindWithValidate,
+// it can be replaced before the binding
+var Validator StructValidator = &defaultValidator{
+	setups: []ValidateSetup{registerRequestValidations},
+}
+
+// pkgValidator forwards to the package level Validator
+type pkgValidator struct{}
+
+func (pkgValidator) ValidateStruct(obj interface{}) error {
+	return Validator.ValidateStruct(obj)
+}
+
+func (pkgValidator) ValidateStructCtx(ctx context.Context, obj interface{}) error {
+	return validateStruct(ctx, Validator, obj)
+}
+
+// validateStruct calls ValidateStructCtx if v implements ContextStructValidator
+func validateStruct(ctx context.Context, v StructValidator, obj interface{}) error {
+	if cv, ok := v.(ContextStructValidator); ok {
+		return cv.ValidateStructCtx(ctx, obj)
+	}
+	return v.ValidateStruct(obj)
+}
+
+// ValidateSetup configures the go-playground validator of Gbind, see WithValidateSetup
+type ValidateSetup func(v *validator.Validate) error
 
 type defaultValidator struct {
 	once     sync.Once
 	validate *validator.Validate
+	// lock guards the registrations, the validator of go-playground can not register while validating
+	lock sync.RWMutex
+	// tagName validate tag name, "validate" if empty
+	tagName string
+	// setups are applied in order when the validator is initialized
+	setups []ValidateSetup
+	// initErr the first error returned by setups
+	initErr error
 }
 
-func (v *defaultValidator) lazyinit() {
+func (v *defaultValidator) lazyinit() error {
 	v.once.Do(func() {
 		v.validate = validator.New()
-		v.validate.SetTagName("validate")
+		tagName := v.tagName
+		if tagName == "" {
+			tagName = defaultValidateTag
+		}
+		v.validate.SetTagName(tagName)
+		for _, setup := range v.setups {
+			if err := setup(v.validate); err != nil {
+				v.initErr = e("validator setup: %v", err)
+				return
+			}
+		}
 	})
+	return v.initErr
+}
+
+// ElemValidateError the validation error of an element in a slice, array or map
+type ElemValidateError struct {
+	// Index the index of the slice or array, or the key of the map
+	Index string
+	Err   error
 }
 
-type sliceValidateError []error
+// ElemValidateErrors the validation errors of the elements in a slice, array or map, sorted by the index
+type ElemValidateErrors []ElemValidateError
 
-func (err sliceValidateError) Error() string {
-	var errMsgs []string
-	for i, e := range err {
-		if e == nil {
-			continue
-		}
-		errMsgs = append(errMsgs, fmt.Sprintf("[%d]: %s", i, e.Error()))
+func (errs ElemValidateErrors) Error() string {
+	errMsgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		errMsgs = append(errMsgs, fmt.Sprintf("[%s]: %s", e.Index, e.Err.Error()))
 	}
 	return strings.Join(errMsgs, "\n")
 }
 
-var _ StructValidator = &defaultValidator{}
+var (
+	_ ContextStructValidator = &defaultValidator{}
+	_ ContextStructValidator = pkgValidator{}
+)
 
-// ValidateStruct receives any kind of type, but only performed struct or pointer to struct type.
+// ValidateStruct receives any kind of type, but only performed struct, pointer to struct type
+// and the slice, array or map of them.
 func (v *defaultValidator) ValidateStruct(obj interface{}) error {
+	return v.ValidateStructCtx(context.Background(), obj)
+}
+
+// ValidateStructCtx receives any kind of type, but only performed struct, pointer to struct type
+// and the slice, array or map of them, ctx is passed to the validations registered with RegisterCustomValidationCtx
+func (v *defaultValidator) ValidateStructCtx(ctx context.Context, obj interface{}) error {
 	if obj == nil {
 		return nil
 	}
@@ -53,34 +122,64 @@ func (v *defaultValidator) ValidateStruct(obj interface{}) error {
 	value := reflect.ValueOf(obj)
 	switch value.Kind() {
 	case reflect.Ptr:
-		return v.ValidateStruct(value.Elem().Interface())
+		if value.IsNil() {
+			return nil
+		}
+		return v.ValidateStructCtx(ctx, value.Elem().Interface())
 	case reflect.Struct:
-		return v.validateStruct(obj)
-	// case reflect.Slice, reflect.Array:
-	//	count := value.Len()
-	//	validateRet := make(sliceValidateError, 0)
-	//	for i := 0; i < count; i++ {
-	//		if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
-	//			validateRet = append(validateRet, err)
-	//		}
-	//	}
-	//	if len(validateRet) == 0 {
-	//		return nil
-	//	}
-	//	return validateRet
+		return v.validateStruct(ctx, obj)
+	case reflect.Slice, reflect.Array:
+		var errs ElemValidateErrors
+		for i := 0; i < value.Len(); i++ {
+			if err := v.ValidateStructCtx(ctx, value.Index(i).Interface()); err != nil {
+				errs = append(errs, ElemValidateError{Index: strconv.Itoa(i), Err: err})
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs
+	case reflect.Map:
+		var errs ElemValidateErrors
+		for _, k := range value.MapKeys() {
+			if err := v.ValidateStructCtx(ctx, value.MapIndex(k).Interface()); err != nil {
+				errs = append(errs, ElemValidateError{Index: fmt.Sprint(k.Interface()), Err: err})
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+		return errs
 	default:
 		return nil
 	}
 }
 
 // ValidateStruct receives struct type
-func (v *defaultValidator) validateStruct(obj interface{}) error {
-	v.lazyinit()
-	// v.validate.RegisterValidation()
-	return v.validate.Struct(obj)
+func (v *defaultValidator) validateStruct(ctx context.Context, obj interface{}) error {
+	if err := v.lazyinit(); err != nil {
+		return err
+	}
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.validate.StructCtx(ctx, obj)
 }
 
 func (v *defaultValidator) registerCustomValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) error {
-	v.lazyinit()
+	if err := v.lazyinit(); err != nil {
+		return err
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
 	return v.validate.RegisterValidation(tag, fn, callValidationEvenIfNull...)
 }
+
+func (v *defaultValidator) registerCustomValidationCtx(tag string, fn validator.FuncCtx, callValidationEvenIfNull ...bool) error {
+	if err := v.lazyinit(); err != nil {
+		return err
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.validate.RegisterValidationCtx(tag, fn, callValidationEvenIfNull...)
+}